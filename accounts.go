@@ -0,0 +1,255 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	labelsModule "github.com/brianosaurus/challenge2/labels"
+	vestingModule "github.com/brianosaurus/challenge2/vesting"
+)
+
+// AccountFilter picks the vesting accounts to export, empty fields match every account
+type AccountFilter struct {
+	Addresses []string
+	Type      string
+	// the least original vesting of an account
+	Minimum sdk.Dec
+}
+
+// Unlock is one day of the schedule of an account in json
+type Unlock struct {
+	Day    int    `json:"day"`
+	Date   string `json:"date"`
+	Amount string `json:"amount"`
+}
+
+// AccountRecord is a vesting account in json. Amounts are strings of whole tokens.
+type AccountRecord struct {
+	Address          string   `json:"address"`
+	Type             string   `json:"type"`
+	Label            string   `json:"label,omitempty"`
+	Denom            string   `json:"denom"`
+	OriginalVesting  string   `json:"original_vesting"`
+	Vested           string   `json:"vested"`
+	Unvested         string   `json:"unvested"`
+	StartTime        string   `json:"start_time,omitempty"`
+	EndTime          string   `json:"end_time"`
+	NextUnlock       string   `json:"next_unlock,omitempty"`
+	NextUnlockAmount string   `json:"next_unlock_amount"`
+	Schedule         []Unlock `json:"schedule"`
+}
+
+// FilterAccounts returns the accounts matching the filter
+func FilterAccounts(accounts []vestingModule.Account, filter AccountFilter) []vestingModule.Account {
+	filtered := []vestingModule.Account{}
+
+	for _, account := range accounts {
+		if len(filter.Addresses) > 0 && !contains(filter.Addresses, account.Address) {
+			continue
+		}
+
+		if filter.Type != "" && filter.Type != account.Type {
+			continue
+		}
+
+		if !filter.Minimum.IsNil() && sdk.NewDecFromInt(account.OriginalVesting).LT(filter.Minimum) {
+			continue
+		}
+
+		filtered = append(filtered, account)
+	}
+
+	return filtered
+}
+
+// NewAccountRecord turns the status of an account into a json record with its schedule in order
+func NewAccountRecord(status vestingModule.Status, at int64) AccountRecord {
+	record := AccountRecord{
+		Address:          status.Address,
+		Type:             status.Type,
+		Denom:            status.Denom,
+		OriginalVesting:  status.OriginalVesting.String(),
+		Vested:           status.Vested.RoundInt().String(),
+		Unvested:         status.Unvested.RoundInt().String(),
+		EndTime:          timestamp(status.EndTime),
+		NextUnlockAmount: status.NextUnlockAmount.RoundInt().String(),
+		Schedule:         []Unlock{},
+	}
+
+	if status.Type == vestingModule.ACCOUNT_CONTINUOUS {
+		record.StartTime = timestamp(status.StartTime)
+	}
+
+	if status.NextUnlock != 0 {
+		record.NextUnlock = timestamp(status.NextUnlock)
+	}
+
+	days := []int{}
+	for day := range status.Schedule {
+		days = append(days, day)
+	}
+	sort.Ints(days)
+
+	for _, day := range days {
+		record.Schedule = append(record.Schedule, Unlock{
+			Day:    day,
+			Date:   time.Unix(at+int64(day)*86400, 0).UTC().Format("2006-01-02"),
+			Amount: status.Schedule[day].RoundInt().String(),
+		})
+	}
+
+	return record
+}
+
+// WriteAccountsCSV writes one line per account without the schedule
+func WriteAccountsCSV(writer *csv.Writer, records []AccountRecord) {
+	defer writer.Flush()
+
+	writer.Write([]string{"address", "type", "label", "denom", "original_vesting", "vested", "unvested", "start_time", "end_time",
+		"next_unlock", "next_unlock_amount"})
+
+	for _, record := range records {
+		writer.Write([]string{record.Address, record.Type, record.Label, record.Denom, record.OriginalVesting, record.Vested, record.Unvested,
+			record.StartTime, record.EndTime, record.NextUnlock, record.NextUnlockAmount})
+	}
+}
+
+// WriteAccountsJSON writes the accounts with their schedules as one json document
+func WriteAccountsJSON(writer io.Writer, records []AccountRecord) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(records)
+}
+
+// runAccounts exports the vested and unvested tokens, the next unlock and the schedule of every vesting account
+func runAccounts(args []string) {
+	flags := flag.NewFlagSet("accounts", flag.ExitOnError)
+	format := flags.String("format", "csv", "the output format: csv or json (json includes the daily schedule)")
+	out := flags.String("out", "", "the file to write the accounts to (defaults to vesting_accounts.<format>)")
+	addresses := flags.String("address", "", "only these addresses, separated by commas")
+	accountType := flags.String("type", "", "only accounts of this type: continuous or delayed")
+	minimum := flags.String("min", "", "only accounts with at least this original vesting")
+	labelsFile := flags.String("labels", "", "a csv or yaml file labeling addresses with cohorts")
+	genesisFile := flags.String("genesis", "genesis.json", "the genesis file to analyze")
+	scenarioFile := flags.String("scenario", "", "a scenario file with the anchor of the schedule (the first scenario is used)")
+	flags.Parse(args)
+
+	if *format != "csv" && *format != "json" {
+		fmt.Printf("Unknown format %q\n", *format)
+		return
+	}
+
+	if *accountType != "" && *accountType != vestingModule.ACCOUNT_CONTINUOUS && *accountType != vestingModule.ACCOUNT_DELAYED {
+		fmt.Printf("Unknown account type %q\n", *accountType)
+		return
+	}
+
+	if *out == "" {
+		*out = "vesting_accounts." + *format
+	}
+
+	filter := AccountFilter{Type: *accountType}
+
+	if *addresses != "" {
+		for _, address := range strings.Split(*addresses, ",") {
+			filter.Addresses = append(filter.Addresses, strings.TrimSpace(address))
+		}
+	}
+
+	if *minimum != "" {
+		var err error
+		filter.Minimum, err = sdk.NewDecFromStr(*minimum)
+		if err != nil {
+			fmt.Println("Error parsing min:", err)
+			return
+		}
+	}
+
+	var labels labelsModule.Labels
+	if *labelsFile != "" {
+		var err error
+		labels, err = labelsModule.Load(*labelsFile)
+		if err != nil {
+			fmt.Println("Error reading labels file:", err)
+			return
+		}
+	}
+
+	scenario, err := firstScenario(*scenarioFile)
+	if err != nil {
+		fmt.Println("Error reading scenario file:", err)
+		return
+	}
+
+	genesis, err := ReadGenesis(*genesisFile)
+	if err != nil {
+		fmt.Println("Error reading genesis file:", err)
+		return
+	}
+
+	config, err := scenario.Config(genesis.AppState)
+	if err != nil {
+		fmt.Println("Error in scenario:", err)
+		return
+	}
+
+	continuousAccounts, delayedAccounts := vestingModule.GetVestingAccounts(scenario.AppState(genesis.AppState))
+	accounts := FilterAccounts(vestingModule.GetAccounts(continuousAccounts, delayedAccounts), filter)
+
+	records := []AccountRecord{}
+	for _, account := range accounts {
+		record := NewAccountRecord(account.StatusAt(config.Anchor), config.Anchor)
+		if labels != nil {
+			record.Label = labels.Label(account.Address)
+		}
+
+		records = append(records, record)
+	}
+
+	file, err := os.Create(*out)
+	if err != nil {
+		fmt.Println("Error creating output file")
+		return
+	}
+	defer file.Close()
+
+	switch *format {
+	case "csv":
+		WriteAccountsCSV(csv.NewWriter(file), records)
+	case "json":
+		err = WriteAccountsJSON(file, records)
+	}
+
+	if err != nil {
+		fmt.Println("Error writing accounts:", err)
+		return
+	}
+
+	fmt.Printf("Wrote %d accounts to %s\n", len(records), *out)
+	fmt.Printf("\nDone\n")
+}
+
+// timestamp formats a unix timestamp as RFC 3339 in UTC
+func timestamp(unix int64) string {
+	return time.Unix(unix, 0).UTC().Format(time.RFC3339)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
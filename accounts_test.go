@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+
+	vestingModule "github.com/brianosaurus/challenge2/vesting"
+)
+
+const ACCOUNTS_ANCHOR = 1669075200 // 2022-11-22
+
+var ACCOUNTS = []vestingModule.Account{
+	{Address: "umee1a", Type: vestingModule.ACCOUNT_DELAYED, Denom: "uumee", OriginalVesting: sdk.NewInt(5000000), EndTime: ACCOUNTS_ANCHOR + 10*86400},
+	{Address: "umee1b", Type: vestingModule.ACCOUNT_CONTINUOUS, Denom: "uumee", OriginalVesting: sdk.NewInt(17280 * 3),
+		StartTime: ACCOUNTS_ANCHOR - 86400, EndTime: ACCOUNTS_ANCHOR + 2*86400},
+	{Address: "umee1c", Type: vestingModule.ACCOUNT_DELAYED, Denom: "uumee", OriginalVesting: sdk.NewInt(100), EndTime: ACCOUNTS_ANCHOR + 86400},
+}
+
+func TestFilterAccounts(t *testing.T) {
+	assert.Equal(t, 3, len(FilterAccounts(ACCOUNTS, AccountFilter{})))
+
+	filtered := FilterAccounts(ACCOUNTS, AccountFilter{Type: vestingModule.ACCOUNT_DELAYED, Minimum: sdk.NewDec(1000)})
+	assert.Equal(t, 1, len(filtered))
+	assert.Equal(t, "umee1a", filtered[0].Address)
+
+	filtered = FilterAccounts(ACCOUNTS, AccountFilter{Addresses: []string{"umee1b", "umee1c"}})
+	assert.Equal(t, 2, len(filtered))
+	assert.Equal(t, "umee1b", filtered[0].Address)
+}
+
+func TestWriteAccounts(t *testing.T) {
+	records := []AccountRecord{}
+	for _, account := range ACCOUNTS[:2] {
+		records = append(records, NewAccountRecord(account.StatusAt(ACCOUNTS_ANCHOR), ACCOUNTS_ANCHOR))
+	}
+
+	assert.Equal(t, "2022-12-02T00:00:00Z", records[0].NextUnlock)
+	assert.Equal(t, "0", records[0].Vested)
+	assert.Equal(t, []Unlock{{Day: 10, Date: "2022-12-02", Amount: "5000000"}}, records[0].Schedule)
+
+	// one day of three has vested
+	assert.Equal(t, "17280", records[1].Vested)
+	assert.Equal(t, "34560", records[1].Unvested)
+	assert.Equal(t, "2022-11-21T00:00:00Z", records[1].StartTime)
+	assert.Equal(t, 2, len(records[1].Schedule))
+
+	var b bytes.Buffer
+	WriteAccountsCSV(csv.NewWriter(&b), records)
+
+	lines, err := csv.NewReader(strings.NewReader(b.String())).ReadAll()
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(lines))
+	assert.Equal(t, "next_unlock_amount", lines[0][10])
+	assert.Equal(t, []string{"umee1a", "delayed", "", "uumee", "5000000", "0", "5000000", "", "2022-12-02T00:00:00Z",
+		"2022-12-02T00:00:00Z", "5000000"}, lines[1])
+
+	b.Reset()
+	err = WriteAccountsJSON(&b, records)
+	assert.Nil(t, err)
+
+	decoded := []AccountRecord{}
+	assert.Nil(t, json.Unmarshal(b.Bytes(), &decoded))
+	assert.Equal(t, records, decoded)
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/brianosaurus/challenge2/projection"
+	scenarioModule "github.com/brianosaurus/challenge2/scenario"
+	vestingModule "github.com/brianosaurus/challenge2/vesting"
+)
+
+// State is the supply at a point in time, simulated from genesis. Time is when the simulated row ends,
+// the last one at or before the time asked for. Amounts are strings of whole tokens.
+type State struct {
+	Time              time.Time `json:"time"`
+	Day               int       `json:"day"`
+	Height            int64     `json:"height,omitempty"`
+	Inflation         string    `json:"inflation"`
+	TotalSupply       string    `json:"total_supply"`
+	CirculatingSupply string    `json:"circulating_supply"`
+	Vested            string    `json:"vested"`
+	Unvested          string    `json:"unvested"`
+	StakingRewards    string    `json:"staking_rewards"`
+	Liquid            string    `json:"liquid"`
+	StakedVested      string    `json:"staked_vested"`
+	StakedUnvested    string    `json:"staked_unvested"`
+	Locked            string    `json:"locked"`
+}
+
+// simulateTo runs the projection from the genesis time up to at with the assumptions of the scenario, its anchor
+// is replaced by the genesis time. It returns the row of the last full day before at and the days since genesis.
+func simulateTo(genesis Genesis, scenario scenarioModule.Scenario, at time.Time) (projection.Row, int, error) {
+	if genesis.GenesisTime.IsZero() {
+		return projection.Row{}, 0, errors.New("genesis has no genesis_time to start from")
+	}
+
+	if at.Before(genesis.GenesisTime) {
+		return projection.Row{}, 0, fmt.Errorf("%s is before the genesis time %s", at.UTC().Format(time.RFC3339),
+			genesis.GenesisTime.UTC().Format(time.RFC3339))
+	}
+
+	scenario.Anchor = genesis.GenesisTime.UTC().Format(time.RFC3339)
+	scenario.Until = ""
+	scenario.Days = 1
+	scenario.Granularity = projection.GRANULARITY_DAY
+
+	anchor := genesis.GenesisTime.Unix()
+	day := int((at.Unix() - anchor) / 86400)
+	if day > 0 {
+		scenario.Days = day
+	}
+
+	config, err := scenario.Config(genesis.AppState)
+	if err != nil {
+		return projection.Row{}, 0, err
+	}
+
+	genesis.SetHeights(&config)
+
+	projected := projection.Run(config)
+
+	// row N ends N days after genesis
+	return projected.Rows[day], day, nil
+}
+
+// StateAt runs the projection from the genesis time up to at and returns the state then
+func StateAt(genesis Genesis, scenario scenarioModule.Scenario, at time.Time) (State, error) {
+	row, day, err := simulateTo(genesis, scenario, at)
+	if err != nil {
+		return State{}, err
+	}
+
+	continuousAccounts, delayedAccounts := vestingModule.GetVestingAccounts(scenario.AppState(genesis.AppState))
+	originalVesting := sdk.ZeroDec()
+	for _, account := range vestingModule.GetAccounts(continuousAccounts, delayedAccounts) {
+		originalVesting = originalVesting.Add(sdk.NewDecFromInt(account.OriginalVesting))
+	}
+
+	unvested := row.TotalSupply.Sub(row.CirculatingSupply)
+
+	return State{
+		Time:              time.Unix(row.Time, 0).UTC(),
+		Day:               day,
+		Height:            row.Height,
+		Inflation:         row.Inflation.String(),
+		TotalSupply:       row.TotalSupply.RoundInt().String(),
+		CirculatingSupply: row.CirculatingSupply.RoundInt().String(),
+		Vested:            originalVesting.Sub(unvested).RoundInt().String(),
+		Unvested:          unvested.RoundInt().String(),
+		StakingRewards:    row.StakingRewards.RoundInt().String(),
+		Liquid:            row.Liquid.RoundInt().String(),
+		StakedVested:      row.StakedVested.RoundInt().String(),
+		StakedUnvested:    row.StakedUnvested.RoundInt().String(),
+		Locked:            row.Locked.RoundInt().String(),
+	}, nil
+}
+
+// WriteState writes the state as an aligned table
+func WriteState(writer io.Writer, state State) error {
+	table := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0)
+
+	lines := [][2]string{
+		{"Time", state.Time.Format(time.RFC3339)},
+		{"Days Since Genesis", fmt.Sprint(state.Day)},
+	}
+
+	if state.Height != 0 {
+		lines = append(lines, [2]string{"Block Height", fmt.Sprint(state.Height)})
+	}
+
+	lines = append(lines,
+		[2]string{"Inflation", state.Inflation},
+		[2]string{"Total Supply", state.TotalSupply},
+		[2]string{"Circulating Supply", state.CirculatingSupply},
+		[2]string{"Vested", state.Vested},
+		[2]string{"Unvested", state.Unvested},
+		[2]string{"Staking Rewards", state.StakingRewards},
+		[2]string{"Liquid", state.Liquid},
+		[2]string{"Staked Vested", state.StakedVested},
+		[2]string{"Staked Unvested", state.StakedUnvested},
+		[2]string{"Locked", state.Locked},
+	)
+
+	for _, line := range lines {
+		if _, err := fmt.Fprintf(table, "%s\t%s\n", line[0], line[1]); err != nil {
+			return err
+		}
+	}
+
+	return table.Flush()
+}
+
+// runAt prints the supply at any time after genesis, e.g. the day of a listing
+func runAt(args []string) {
+	flags := flag.NewFlagSet("at", flag.ExitOnError)
+	at := flags.String("time", "", "the time to reconstruct the supply at, RFC3339 or YYYY-MM-DD")
+	format := flags.String("format", "text", "the output format: text or json")
+	genesisFile := flags.String("genesis", "genesis.json", "the genesis file to analyze")
+	scenarioFile := flags.String("scenario", "", "a scenario file with the assumptions of the simulation (the first scenario is used, its anchor is ignored)")
+	flags.Parse(args)
+
+	if *format != "text" && *format != "json" {
+		fmt.Printf("Unknown format %q\n", *format)
+		return
+	}
+
+	if *at == "" {
+		fmt.Println("-time is required")
+		return
+	}
+
+	when, err := scenarioModule.ParseTime(*at)
+	if err != nil {
+		fmt.Println("Error parsing time:", err)
+		return
+	}
+
+	scenario, err := firstScenario(*scenarioFile)
+	if err != nil {
+		fmt.Println("Error reading scenario file:", err)
+		return
+	}
+
+	genesis, err := ReadGenesis(*genesisFile)
+	if err != nil {
+		fmt.Println("Error reading genesis file:", err)
+		return
+	}
+
+	state, err := StateAt(genesis, scenario, when)
+	if err != nil {
+		fmt.Println("Error reconstructing the supply:", err)
+		return
+	}
+
+	if *format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(state)
+	} else {
+		err = WriteState(os.Stdout, state)
+	}
+
+	if err != nil {
+		fmt.Println("Error writing state:", err)
+	}
+}
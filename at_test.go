@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+
+	scenarioModule "github.com/brianosaurus/challenge2/scenario"
+)
+
+// newTestAppState is a genesis app state with the vesting accounts, balances, gen_txs and minter of the test fixtures
+func newTestAppState(t *testing.T) map[string]interface{} {
+	var appState = make(map[string]interface{})
+
+	for _, raw := range []string{MINTER, AUTH_VESTING_ACCOUNTS, BANK_BALANCES, STAKING_ACCOUNTS} {
+		err := json.Unmarshal([]byte(raw), &appState)
+		if err != nil {
+			t.Log("Error decoding json")
+			t.FailNow()
+		}
+	}
+
+	var params = make(map[string]interface{})
+	err := json.Unmarshal([]byte(PARAMS), &params)
+	if err != nil {
+		t.Log("Error decoding json")
+		t.FailNow()
+	}
+	appState["mint"].(map[string]interface{})["params"] = params["params"]
+
+	return appState
+}
+
+func TestStateAt(t *testing.T) {
+	appState := newTestAppState(t)
+
+	// genesis is when the continuous account starts vesting
+	genesis := Genesis{AppState: appState, ChainID: "umee-1", GenesisTime: time.Unix(1660582800, 0)}
+	originalVesting := sdk.NewInt(309282000000 + 11250000000000)
+
+	state, err := StateAt(genesis, scenarioModule.Scenario{}, genesis.GenesisTime)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, state.Day)
+	assert.Equal(t, genesis.GenesisTime.UTC(), state.Time)
+	assert.Equal(t, "0", state.Vested)
+	assert.Equal(t, originalVesting.String(), state.Unvested)
+	assert.Equal(t, "0", state.StakingRewards)
+
+	// the delayed account unlocked on 2023-02-15, the state is the end of the last full day before the time asked for
+	listing, _ := scenarioModule.ParseTime("2023-03-01T12:00:00Z")
+	state, err = StateAt(genesis, scenarioModule.Scenario{}, listing)
+	assert.Nil(t, err)
+	assert.Equal(t, 197, state.Day)
+	assert.False(t, state.Time.After(listing))
+	assert.Less(t, listing.Unix()-state.Time.Unix(), int64(86400))
+
+	vested, ok := sdk.NewIntFromString(state.Vested)
+	assert.True(t, ok)
+	unvested, ok := sdk.NewIntFromString(state.Unvested)
+	assert.True(t, ok)
+	assert.Equal(t, originalVesting, vested.Add(unvested))
+	assert.True(t, vested.GT(sdk.NewInt(309282000000)))
+
+	var buf bytes.Buffer
+	assert.Nil(t, WriteState(&buf, state))
+	assert.Contains(t, buf.String(), "Circulating Supply  "+state.CirculatingSupply+"\n")
+
+	// there is nothing to reconstruct before genesis or without a genesis time
+	_, err = StateAt(genesis, scenarioModule.Scenario{}, genesis.GenesisTime.Add(-time.Second))
+	assert.NotNil(t, err)
+	_, err = StateAt(Genesis{AppState: appState}, scenarioModule.Scenario{}, listing)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	mintModule "github.com/brianosaurus/challenge2/mint"
+	scenarioModule "github.com/brianosaurus/challenge2/scenario"
+	stakingModule "github.com/brianosaurus/challenge2/staking"
+	vestingModule "github.com/brianosaurus/challenge2/vesting"
+)
+
+// Snapshot is a later export of the chain state and the time it was taken
+type Snapshot struct {
+	Path    string
+	Time    time.Time
+	Genesis Genesis
+}
+
+// Deviation compares the projection with a snapshot. The projection is the end of the last full day before the
+// snapshot. Supply deviates relative to the actual supply, bonded ratio and inflation by their difference.
+// ActualInflation is nil when the snapshot has no minter inflation, ActualBondedRatio when it has no staking state.
+type Deviation struct {
+	Path                 string
+	Time                 time.Time
+	Day                  int
+	ProjectedSupply      sdk.Dec
+	ActualSupply         sdk.Dec
+	ProjectedBondedRatio sdk.Dec
+	ActualBondedRatio    sdk.Dec
+	ProjectedInflation   sdk.Dec
+	ActualInflation      sdk.Dec
+}
+
+// SupplyDeviation is how far the projected supply is off relative to the actual supply
+func (d Deviation) SupplyDeviation() sdk.Dec {
+	if d.ActualSupply.IsZero() {
+		return sdk.ZeroDec()
+	}
+
+	return d.ProjectedSupply.Sub(d.ActualSupply).Quo(d.ActualSupply)
+}
+
+// ReadSnapshot reads an exported genesis from path, or path@time when the export does not say when it was taken.
+// Without a time the genesis_time of the export is used, some chains set it to the time of the export.
+func ReadSnapshot(arg string, genesis Genesis) (Snapshot, error) {
+	path, at, hasTime := strings.Cut(arg, "@")
+
+	exported, err := ReadGenesis(path)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	snapshot := Snapshot{Path: path, Time: exported.GenesisTime, Genesis: exported}
+
+	if hasTime {
+		snapshot.Time, err = scenarioModule.ParseTime(at)
+		if err != nil {
+			return Snapshot{}, fmt.Errorf("%s: %w", path, err)
+		}
+	} else if !exported.GenesisTime.After(genesis.GenesisTime) {
+		return Snapshot{}, fmt.Errorf("%s: genesis_time is not after the original genesis, give the time of the export as %s@<time>", path, path)
+	}
+
+	return snapshot, nil
+}
+
+// Backtest projects genesis up to the time of each snapshot and compares the projection with what the chain exported
+func Backtest(genesis Genesis, scenario scenarioModule.Scenario, snapshots []Snapshot) ([]Deviation, error) {
+	deviations := []Deviation{}
+
+	for _, snapshot := range snapshots {
+		row, day, err := simulateTo(genesis, scenario, snapshot.Time)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", snapshot.Path, err)
+		}
+
+		denom := bondDenom(snapshot.Genesis.AppState)
+		if denom == "" {
+			return nil, fmt.Errorf("%s: no bond or mint denom", snapshot.Path)
+		}
+
+		deviation := Deviation{
+			Path:                 snapshot.Path,
+			Time:                 snapshot.Time,
+			Day:                  day,
+			ProjectedSupply:      row.TotalSupply,
+			ActualSupply:         sdk.NewDecFromInt(vestingModule.GetSupply(snapshot.Genesis.AppState, denom)),
+			ProjectedBondedRatio: row.StakedVested.Add(row.StakedUnvested).Quo(row.TotalSupply),
+			ProjectedInflation:   row.Inflation,
+		}
+
+		if bonded, ok := stakingModule.GetBondedTokens(snapshot.Genesis.AppState); ok && deviation.ActualSupply.IsPositive() {
+			deviation.ActualBondedRatio = bonded.Quo(deviation.ActualSupply)
+		}
+
+		if inflation, ok := mintModule.GetInflation(snapshot.Genesis.AppState); ok {
+			deviation.ActualInflation = inflation
+		}
+
+		deviations = append(deviations, deviation)
+	}
+
+	return deviations, nil
+}
+
+// WriteBacktestCSV writes one line per snapshot, unknown actual values are empty
+func WriteBacktestCSV(writer *csv.Writer, deviations []Deviation) {
+	defer writer.Flush()
+
+	writer.Write([]string{"File", "Time", "Days Since Genesis", "Projected Total Supply", "Actual Total Supply", "Total Supply Deviation",
+		"Projected Bonded Ratio", "Actual Bonded Ratio", "Bonded Ratio Deviation", "Projected Inflation", "Actual Inflation",
+		"Inflation Deviation"})
+
+	for _, d := range deviations {
+		writer.Write([]string{d.Path, d.Time.UTC().Format(time.RFC3339), fmt.Sprint(d.Day),
+			d.ProjectedSupply.RoundInt().String(), d.ActualSupply.RoundInt().String(), d.SupplyDeviation().String(),
+			d.ProjectedBondedRatio.String(), decString(d.ActualBondedRatio), difference(d.ProjectedBondedRatio, d.ActualBondedRatio),
+			d.ProjectedInflation.String(), decString(d.ActualInflation), difference(d.ProjectedInflation, d.ActualInflation)})
+	}
+}
+
+// WriteBacktest writes the deviations as an aligned table, supply in percent and ratios in percentage points
+func WriteBacktest(writer io.Writer, deviations []Deviation) error {
+	table := tabwriter.NewWriter(writer, 0, 0, 2, ' ', tabwriter.AlignRight)
+
+	fmt.Fprintln(table, "File\tDate\tDay\tProjected Supply\tActual Supply\tDeviation\tProjected Bonded\tActual Bonded\tDeviation\t"+
+		"Projected Inflation\tActual Inflation\tDeviation\t")
+
+	for _, d := range deviations {
+		fmt.Fprintf(table, "%s\t%s\t%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t\n", d.Path, d.Time.UTC().Format("2006-01-02"), d.Day,
+			d.ProjectedSupply.RoundInt(), d.ActualSupply.RoundInt(), percentString(d.SupplyDeviation()),
+			percentString(d.ProjectedBondedRatio), percentString(d.ActualBondedRatio), points(d.ProjectedBondedRatio, d.ActualBondedRatio),
+			percentString(d.ProjectedInflation), percentString(d.ActualInflation), points(d.ProjectedInflation, d.ActualInflation))
+	}
+
+	return table.Flush()
+}
+
+// runBacktest projects the original genesis up to each later export and reports how far the projection was off
+func runBacktest(args []string) {
+	flags := flag.NewFlagSet("backtest", flag.ExitOnError)
+	genesisFile := flags.String("genesis", "genesis.json", "the original genesis file")
+	scenarioFile := flags.String("scenario", "", "a scenario file with the assumptions of the projection (the first scenario is used, its anchor is ignored)")
+	out := flags.String("out", "", "a csv file to also write the deviations to")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: genesisAnalyzer backtest [flags] exported.json[@time] ...")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return
+	}
+
+	scenario, err := firstScenario(*scenarioFile)
+	if err != nil {
+		fmt.Println("Error reading scenario file:", err)
+		return
+	}
+
+	genesis, err := ReadGenesis(*genesisFile)
+	if err != nil {
+		fmt.Println("Error reading genesis file:", err)
+		return
+	}
+
+	snapshots := []Snapshot{}
+	for _, arg := range flags.Args() {
+		snapshot, err := ReadSnapshot(arg, genesis)
+		if err != nil {
+			fmt.Println("Error reading exported state:", err)
+			return
+		}
+
+		snapshots = append(snapshots, snapshot)
+	}
+
+	deviations, err := Backtest(genesis, scenario, snapshots)
+	if err != nil {
+		fmt.Println("Error backtesting:", err)
+		return
+	}
+
+	if err = WriteBacktest(os.Stdout, deviations); err != nil {
+		fmt.Println("Error writing deviations:", err)
+		return
+	}
+
+	if *out != "" {
+		file, err := os.Create(*out)
+		if err != nil {
+			fmt.Println("Error creating csv file")
+			return
+		}
+		defer file.Close()
+
+		WriteBacktestCSV(csv.NewWriter(file), deviations)
+	}
+
+	fmt.Printf("\nDone\n")
+}
+
+// bondDenom is the staking bond denom of the app state, or the mint denom when there are no staking params
+func bondDenom(appState map[string]interface{}) string {
+	for _, module := range []string{"staking", "mint"} {
+		state, _ := appState[module].(map[string]interface{})
+		params, _ := state["params"].(map[string]interface{})
+
+		for _, key := range []string{"bond_denom", "mint_denom"} {
+			if denom, ok := params[key].(string); ok && denom != "" {
+				return denom
+			}
+		}
+	}
+
+	return ""
+}
+
+func decString(value sdk.Dec) string {
+	if value.IsNil() {
+		return ""
+	}
+
+	return value.String()
+}
+
+// difference is projected - actual, empty when actual is unknown
+func difference(projected sdk.Dec, actual sdk.Dec) string {
+	if actual.IsNil() {
+		return ""
+	}
+
+	return projected.Sub(actual).String()
+}
+
+func percentString(value sdk.Dec) string {
+	if value.IsNil() {
+		return "-"
+	}
+
+	return fmt.Sprintf("%.2f%%", value.MustFloat64()*100)
+}
+
+// points is the difference in percentage points
+func points(projected sdk.Dec, actual sdk.Dec) string {
+	if actual.IsNil() {
+		return "-"
+	}
+
+	return fmt.Sprintf("%+.2fpp", projected.Sub(actual).MustFloat64()*100)
+}
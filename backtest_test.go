@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+
+	scenarioModule "github.com/brianosaurus/challenge2/scenario"
+)
+
+func TestBacktest(t *testing.T) {
+	genesis := Genesis{AppState: newTestAppState(t), ChainID: "umee-1", GenesisTime: time.Unix(1660582800, 0)}
+
+	// an export 30 days later that keeps its original genesis_time, so the time is given
+	exported := `{"genesis_time": "2022-08-15T17:00:00Z", "chain_id": "umee-1", "initial_height": "518401", "app_state": {
+		"bank": {"balances": [], "supply": [{"denom": "uumee", "amount": "11600000000000"}]},
+		"mint": {"minter": {"inflation": "0.140000000000000000", "annual_provisions": "0"}},
+		"staking": {"params": {"bond_denom": "uumee"}, "validators": [
+			{"operator_address": "umeevaloper1a", "status": "BOND_STATUS_BONDED", "tokens": "2900000000000"},
+			{"operator_address": "umeevaloper1b", "status": "BOND_STATUS_UNBONDED", "tokens": "100000000000"}
+		]}
+	}}`
+	path := filepath.Join(t.TempDir(), "export.json")
+	err := os.WriteFile(path, []byte(exported), 0644)
+	assert.Nil(t, err)
+
+	_, err = ReadSnapshot(path, genesis)
+	assert.NotNil(t, err)
+
+	snapshot, err := ReadSnapshot(path+"@2022-09-14T17:00:00Z", genesis)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1660582800+30*86400), snapshot.Time.Unix())
+
+	deviations, err := Backtest(genesis, scenarioModule.Scenario{}, []Snapshot{snapshot})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(deviations))
+
+	deviation := deviations[0]
+	assert.Equal(t, 30, deviation.Day)
+	assert.Equal(t, sdk.NewDec(11600000000000), deviation.ActualSupply)
+	assert.Equal(t, sdk.MustNewDecFromStr("0.25"), deviation.ActualBondedRatio)
+	assert.Equal(t, sdk.MustNewDecFromStr("0.14"), deviation.ActualInflation)
+
+	// the projection starts from the 11582258000000 tokens of genesis and only the gen_tx stake, a month of
+	// about 13% inflation takes it past the exported supply
+	assert.True(t, deviation.ProjectedSupply.GT(sdk.NewDec(11582258000000)))
+	assert.True(t, deviation.ProjectedSupply.GT(deviation.ActualSupply))
+	assert.True(t, deviation.SupplyDeviation().IsPositive())
+	assert.True(t, deviation.ProjectedBondedRatio.LT(sdk.MustNewDecFromStr("0.001")))
+
+	var buf bytes.Buffer
+	WriteBacktestCSV(csv.NewWriter(&buf), deviations)
+	lines, err := csv.NewReader(strings.NewReader(buf.String())).ReadAll()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(lines))
+	assert.Equal(t, "Total Supply Deviation", lines[0][5])
+	assert.Equal(t, "11600000000000", lines[1][4])
+	assert.Equal(t, "0.250000000000000000", lines[1][7])
+
+	buf.Reset()
+	assert.Nil(t, WriteBacktest(&buf, deviations))
+	assert.Contains(t, buf.String(), "25.00%")
+	assert.Contains(t, buf.String(), "14.00%")
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	calendarModule "github.com/brianosaurus/challenge2/calendar"
+	vestingModule "github.com/brianosaurus/challenge2/vesting"
+)
+
+// runCalendar writes the days with large unlocks to an iCalendar file that calendar apps can import
+func runCalendar(args []string) {
+	flags := flag.NewFlagSet("calendar", flag.ExitOnError)
+	out := flags.String("out", "unlocks.ics", "the .ics file to write the unlocks to")
+	threshold := flags.String("threshold", "0", "only days unlocking more than this many tokens get an event")
+	genesisFile := flags.String("genesis", "genesis.json", "the genesis file to analyze")
+	scenarioFile := flags.String("scenario", "", "a scenario file with the anchor of the schedule (the first scenario is used)")
+	flags.Parse(args)
+
+	minimum, err := sdk.NewDecFromStr(*threshold)
+	if err != nil {
+		fmt.Println("Error parsing threshold:", err)
+		return
+	}
+
+	scenario, err := firstScenario(*scenarioFile)
+	if err != nil {
+		fmt.Println("Error reading scenario file:", err)
+		return
+	}
+
+	genesis, err := ReadGenesis(*genesisFile)
+	if err != nil {
+		fmt.Println("Error reading genesis file:", err)
+		return
+	}
+
+	config, err := scenario.Config(genesis.AppState)
+	if err != nil {
+		fmt.Println("Error in scenario:", err)
+		return
+	}
+
+	continuousAccounts, delayedAccounts := vestingModule.GetVestingAccounts(scenario.AppState(genesis.AppState))
+	accounts := vestingModule.GetAccounts(continuousAccounts, delayedAccounts)
+
+	c := calendarModule.Calendar{
+		ChainID: genesis.ChainID,
+		Created: time.Now(),
+		Unlocks: calendarModule.Unlocks(accounts, config.Anchor, minimum),
+	}
+
+	if len(accounts) > 0 {
+		c.Denom = accounts[0].Denom
+	}
+
+	file, err := os.Create(*out)
+	if err != nil {
+		fmt.Println("Error creating ics file")
+		return
+	}
+	defer file.Close()
+
+	err = c.WriteICS(file)
+	if err != nil {
+		fmt.Println("Error writing calendar:", err)
+		return
+	}
+
+	fmt.Printf("Wrote %d unlocks to %s\n", len(c.Unlocks), *out)
+	fmt.Printf("\nDone\n")
+}
@@ -0,0 +1,160 @@
+package calendar
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	vestingModule "github.com/brianosaurus/challenge2/vesting"
+)
+
+const (
+	// the most accounts listed in the description of one event
+	MAX_ACCOUNTS = 50
+
+	// lines longer than this many bytes are folded, see RFC 5545 section 3.1
+	MAX_LINE = 75
+)
+
+// Contribution is what one account unlocks on the day of an unlock
+type Contribution struct {
+	Address string
+	Type    string
+	Amount  sdk.Dec
+}
+
+// Unlock is a day with large unlocks, Accounts are largest first
+type Unlock struct {
+	Day      int
+	Date     time.Time
+	Amount   sdk.Dec
+	Accounts []Contribution
+}
+
+// Calendar is an iCalendar file of unlocks
+type Calendar struct {
+	ChainID string
+	Denom   string
+	// when the calendar was made, every event needs it
+	Created time.Time
+	Unlocks []Unlock
+}
+
+// Unlocks returns the UTC dates when the vesting accounts unlock more than threshold together. Day counts from the
+// UTC date of anchor and the amounts are the vesting schedule of the projection split at midnight, so delayed
+// accounts unlock on the date of their end time and continuous accounts a little every date they vest.
+func Unlocks(accounts []vestingModule.Account, anchor int64, threshold sdk.Dec) []Unlock {
+	byDay := make(map[int]*Unlock)
+	anchorDate := time.Unix(anchor, 0).UTC().Truncate(time.Hour * 24)
+
+	for _, account := range accounts {
+		for day, amount := range account.ScheduleByDateAt(anchor) {
+			unlock, ok := byDay[day]
+			if !ok {
+				unlock = &Unlock{Day: day, Date: anchorDate.AddDate(0, 0, day), Amount: sdk.ZeroDec()}
+				byDay[day] = unlock
+			}
+
+			unlock.Amount = unlock.Amount.Add(amount)
+			unlock.Accounts = append(unlock.Accounts, Contribution{Address: account.Address, Type: account.Type, Amount: amount})
+		}
+	}
+
+	unlocks := []Unlock{}
+	for _, unlock := range byDay {
+		if unlock.Amount.LTE(threshold) {
+			continue
+		}
+
+		sort.SliceStable(unlock.Accounts, func(i, j int) bool {
+			if unlock.Accounts[i].Amount.Equal(unlock.Accounts[j].Amount) {
+				return unlock.Accounts[i].Address < unlock.Accounts[j].Address
+			}
+
+			return unlock.Accounts[i].Amount.GT(unlock.Accounts[j].Amount)
+		})
+
+		unlocks = append(unlocks, *unlock)
+	}
+
+	sort.Slice(unlocks, func(i, j int) bool {
+		return unlocks[i].Day < unlocks[j].Day
+	})
+
+	return unlocks
+}
+
+// WriteICS writes the calendar with an all day event for every unlock
+func (c Calendar) WriteICS(writer io.Writer) error {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//genesisAnalyzer//unlocks//EN",
+		"CALSCALE:GREGORIAN",
+		"X-WR-CALNAME:" + escape(strings.TrimSpace(c.ChainID+" unlocks")),
+	}
+
+	for _, unlock := range c.Unlocks {
+		date := unlock.Date.Format("20060102")
+
+		description := fmt.Sprintf("%s %s unlock from %d accounts:", unlock.Amount.RoundInt(), c.Denom, len(unlock.Accounts))
+		for i, account := range unlock.Accounts {
+			if i == MAX_ACCOUNTS {
+				description += fmt.Sprintf("\nand %d more", len(unlock.Accounts)-MAX_ACCOUNTS)
+				break
+			}
+
+			description += fmt.Sprintf("\n%s %s (%s)", account.Address, account.Amount.RoundInt(), account.Type)
+		}
+
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			fmt.Sprintf("UID:%s-%s-unlock@genesisAnalyzer", date, c.ChainID),
+			"DTSTAMP:"+c.Created.UTC().Format("20060102T150405Z"),
+			"DTSTART;VALUE=DATE:"+date,
+			"DTEND;VALUE=DATE:"+unlock.Date.AddDate(0, 0, 1).Format("20060102"),
+			"SUMMARY:"+escape(fmt.Sprintf("%s %s unlock", unlock.Amount.RoundInt(), c.Denom)),
+			"DESCRIPTION:"+escape(description),
+			"TRANSP:TRANSPARENT",
+			"END:VEVENT",
+		)
+	}
+
+	lines = append(lines, "END:VCALENDAR")
+
+	for _, line := range lines {
+		if _, err := io.WriteString(writer, fold(line)+"\r\n"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// escape makes text safe for a property value
+func escape(text string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(text)
+}
+
+// fold splits a line into lines of at most MAX_LINE bytes, every line after the first starts with a space
+func fold(line string) string {
+	var b strings.Builder
+
+	length := 0
+	for _, r := range line {
+		size := len(string(r))
+		if length+size > MAX_LINE {
+			b.WriteString("\r\n ")
+			length = 1
+		}
+
+		b.WriteRune(r)
+		length += size
+	}
+
+	return b.String()
+}
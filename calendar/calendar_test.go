@@ -0,0 +1,123 @@
+package calendar
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+
+	vestingModule "github.com/brianosaurus/challenge2/vesting"
+)
+
+const ANCHOR = 1669075200 // 2022-11-22
+
+func TestUnlocks(t *testing.T) {
+	accounts := []vestingModule.Account{
+		{Address: "umee1delayed", Type: vestingModule.ACCOUNT_DELAYED, Denom: "uumee", OriginalVesting: sdk.NewInt(5000000), EndTime: ANCHOR + 10*86400},
+		{Address: "umee1other", Type: vestingModule.ACCOUNT_DELAYED, Denom: "uumee", OriginalVesting: sdk.NewInt(7000000), EndTime: ANCHOR + 10*86400},
+		{Address: "umee1small", Type: vestingModule.ACCOUNT_DELAYED, Denom: "uumee", OriginalVesting: sdk.NewInt(100), EndTime: ANCHOR + 20*86400},
+	}
+
+	unlocks := Unlocks(accounts, ANCHOR, sdk.NewDec(1000))
+	assert.Equal(t, 1, len(unlocks))
+	assert.Equal(t, 10, unlocks[0].Day)
+	assert.Equal(t, "2022-12-02", unlocks[0].Date.Format("2006-01-02"))
+	assert.Equal(t, sdk.NewDec(12000000), unlocks[0].Amount)
+
+	// largest first
+	assert.Equal(t, 2, len(unlocks[0].Accounts))
+	assert.Equal(t, "umee1other", unlocks[0].Accounts[0].Address)
+	assert.Equal(t, "umee1delayed", unlocks[0].Accounts[1].Address)
+
+	// without a threshold every unlock is an event
+	assert.Equal(t, 2, len(Unlocks(accounts, ANCHOR, sdk.ZeroDec())))
+}
+
+func TestUnlocksAnchorTime(t *testing.T) {
+	// 15:00 on 2022-11-22, unlocks still go on the UTC date they happen on
+	anchor := int64(ANCHOR + 15*3600)
+
+	accounts := []vestingModule.Account{
+		{Address: "umee1delayed", Type: vestingModule.ACCOUNT_DELAYED, Denom: "uumee", OriginalVesting: sdk.NewInt(5000000), EndTime: ANCHOR + 10*86400 + 3600},
+		{Address: "umee1continuous", Type: vestingModule.ACCOUNT_CONTINUOUS, Denom: "uumee", OriginalVesting: sdk.NewInt(3000000),
+			StartTime: ANCHOR + 2*86400 + 12*3600, EndTime: ANCHOR + 32*86400},
+		{Address: "umee1started", Type: vestingModule.ACCOUNT_CONTINUOUS, Denom: "uumee", OriginalVesting: sdk.NewInt(1000000),
+			StartTime: ANCHOR - 86400, EndTime: ANCHOR + 32*86400 + 23*3600},
+		{Address: "umee1unlocked", Type: vestingModule.ACCOUNT_DELAYED, Denom: "uumee", OriginalVesting: sdk.NewInt(9000000), EndTime: ANCHOR + 3600},
+	}
+
+	// the delayed account unlocks at 01:00 on 2022-12-02, along with a day of the continuous accounts
+	unlocks := Unlocks(accounts, anchor, sdk.NewDec(1000000))
+	assert.Equal(t, 1, len(unlocks))
+	assert.Equal(t, 10, unlocks[0].Day)
+	assert.Equal(t, "2022-12-02", unlocks[0].Date.Format("2006-01-02"))
+	assert.Equal(t, 3, len(unlocks[0].Accounts))
+	assert.Equal(t, Contribution{Address: "umee1delayed", Type: vestingModule.ACCOUNT_DELAYED, Amount: sdk.NewDec(5000000)}, unlocks[0].Accounts[0])
+
+	// without a threshold there is one event for every date something vests, from the anchor on
+	unlocks = Unlocks(accounts, anchor, sdk.ZeroDec())
+	assert.Equal(t, 33, len(unlocks))
+
+	continuous := sdk.ZeroDec()
+	for day, unlock := range unlocks {
+		assert.Equal(t, day, unlock.Day)
+
+		for _, account := range unlock.Accounts {
+			assert.NotEqual(t, "umee1unlocked", account.Address)
+
+			if account.Address == "umee1continuous" {
+				continuous = continuous.Add(account.Amount)
+			}
+		}
+	}
+
+	// a continuous account unlocks a little every day, not its whole grant at the end
+	assert.Equal(t, sdk.NewDec(3000000), continuous)
+	assert.Equal(t, "2022-12-24", unlocks[32].Date.Format("2006-01-02"))
+	assert.Equal(t, "umee1started", unlocks[32].Accounts[0].Address)
+	assert.True(t, unlocks[32].Amount.LT(sdk.NewDec(100000)))
+
+	// the first date only counts the 9 hours after the anchor
+	assert.Equal(t, "umee1started", unlocks[0].Accounts[0].Address)
+	assert.True(t, unlocks[0].Amount.LT(unlocks[1].Amount.QuoInt64(2)))
+}
+
+func TestWriteICS(t *testing.T) {
+	accounts := []vestingModule.Account{
+		{Address: "umee1agzky2ak6xs5vve3c2wzjtqdq7fwadcgj2mxf9", Type: vestingModule.ACCOUNT_DELAYED, Denom: "uumee", OriginalVesting: sdk.NewInt(5000000), EndTime: ANCHOR + 86400},
+	}
+
+	c := Calendar{
+		ChainID: "umee-1",
+		Denom:   "uumee",
+		Created: time.Unix(ANCHOR, 0),
+		Unlocks: Unlocks(accounts, ANCHOR, sdk.ZeroDec()),
+	}
+
+	var b bytes.Buffer
+	err := c.WriteICS(&b)
+	assert.Nil(t, err)
+
+	ics := b.String()
+	assert.True(t, strings.HasPrefix(ics, "BEGIN:VCALENDAR\r\n"))
+	assert.True(t, strings.HasSuffix(ics, "END:VCALENDAR\r\n"))
+	assert.Contains(t, ics, "UID:20221123-umee-1-unlock@genesisAnalyzer\r\n")
+	assert.Contains(t, ics, "DTSTAMP:20221122T000000Z\r\n")
+	assert.Contains(t, ics, "DTSTART;VALUE=DATE:20221123\r\n")
+	assert.Contains(t, ics, "DTEND;VALUE=DATE:20221124\r\n")
+	assert.Contains(t, ics, "SUMMARY:5000000 uumee unlock\r\n")
+
+	// long lines are folded and unfold back to the description
+	for _, line := range strings.Split(ics, "\r\n") {
+		assert.LessOrEqual(t, len(line), MAX_LINE)
+	}
+	unfolded := strings.ReplaceAll(ics, "\r\n ", "")
+	assert.Contains(t, unfolded, `DESCRIPTION:5000000 uumee unlock from 1 accounts:\numee1agzky2ak6xs5vve3c2wzjtqdq7fwadcgj2mxf9 5000000 (delayed)`)
+}
+
+func TestEscape(t *testing.T) {
+	assert.Equal(t, `a\, b\; c\\d\ne`, escape("a, b; c\\d\ne"))
+}
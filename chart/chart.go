@@ -0,0 +1,318 @@
+package chart
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	WIDTH  = 800
+	HEIGHT = 360
+
+	// space around the plot for the title, the axis labels and the legend
+	MARGIN_LEFT   = 80
+	MARGIN_RIGHT  = 20
+	MARGIN_TOP    = 40
+	MARGIN_BOTTOM = 60
+
+	// lines with more points than this are thinned out, a pixel cannot show more
+	MAX_POINTS = 1000
+
+	Y_TICKS = 5
+	X_TICKS = 6
+)
+
+var (
+	COLORS = []string{"#1f77b4", "#ff7f0e", "#2ca02c", "#d62728", "#9467bd", "#8c564b"}
+
+	AXIS_COLOR = "#444444"
+	GRID_COLOR = "#dddddd"
+	TEXT_COLOR = "#222222"
+)
+
+// Chart is anything that can be drawn onto a canvas
+type Chart interface {
+	draw(c canvas)
+}
+
+// canvas is what the charts are drawn on. Coordinates are pixels from the top left corner.
+type canvas interface {
+	line(x1, y1, x2, y2 float64, color string, width float64)
+	polyline(points [][2]float64, color string, width float64)
+	rect(x, y, width, height float64, color string)
+	text(x, y float64, str string, anchor string, size float64, color string)
+}
+
+// Series is one line of a line chart
+type Series struct {
+	Name   string
+	Values []float64
+}
+
+// LineChart draws one or more series over the same x axis. Labels are the x values and there
+// should be one for each value of a series.
+type LineChart struct {
+	Title  string
+	Labels []string
+	Series []Series
+}
+
+// BarChart draws one bar per label. Horizontal bars are easier to read with long labels like addresses.
+type BarChart struct {
+	Title      string
+	Labels     []string
+	Values     []float64
+	Horizontal bool
+}
+
+// plot is the area inside the margins with the scale of the y axis
+type plot struct {
+	left, top, width, height float64
+	min, max                 float64
+}
+
+func newPlot(values []float64) plot {
+	p := plot{left: MARGIN_LEFT, top: MARGIN_TOP, width: WIDTH - MARGIN_LEFT - MARGIN_RIGHT, height: HEIGHT - MARGIN_TOP - MARGIN_BOTTOM}
+	p.min, p.max = bounds(values)
+
+	return p
+}
+
+func (p plot) y(value float64) float64 {
+	return p.top + p.height - (value-p.min)/(p.max-p.min)*p.height
+}
+
+// drawYAxis draws the y axis with grid lines at even steps
+func (p plot) drawYAxis(c canvas) {
+	for tick := 0; tick <= Y_TICKS; tick++ {
+		value := p.min + (p.max-p.min)*float64(tick)/Y_TICKS
+		y := p.y(value)
+
+		c.line(p.left, y, p.left+p.width, y, GRID_COLOR, 1)
+		c.text(p.left-6, y+4, humanize(value, (p.max-p.min)/Y_TICKS), "end", 11, TEXT_COLOR)
+	}
+
+	c.line(p.left, p.top, p.left, p.top+p.height, AXIS_COLOR, 1)
+	c.line(p.left, p.top+p.height, p.left+p.width, p.top+p.height, AXIS_COLOR, 1)
+}
+
+func drawTitle(c canvas, title string) {
+	c.text(WIDTH/2, 24, title, "middle", 16, TEXT_COLOR)
+}
+
+func (chart LineChart) draw(c canvas) {
+	values := []float64{}
+	for _, series := range chart.Series {
+		values = append(values, series.Values...)
+	}
+
+	p := newPlot(values)
+
+	drawTitle(c, chart.Title)
+	p.drawYAxis(c)
+
+	points := 0
+	for _, series := range chart.Series {
+		if len(series.Values) > points {
+			points = len(series.Values)
+		}
+	}
+
+	x := func(i int) float64 {
+		if points < 2 {
+			return p.left
+		}
+
+		return p.left + float64(i)/float64(points-1)*p.width
+	}
+
+	// a few labels along the x axis, the first and last lined up with the ends of the axis
+	ticks := xTicks(len(chart.Labels))
+	for t, i := range ticks {
+		anchor := "middle"
+		if t == 0 && len(ticks) > 1 {
+			anchor = "start"
+		} else if t == len(ticks)-1 && len(ticks) > 1 {
+			anchor = "end"
+		}
+
+		c.text(x(i), p.top+p.height+18, chart.Labels[i], anchor, 11, TEXT_COLOR)
+	}
+
+	for s, series := range chart.Series {
+		color := COLORS[s%len(COLORS)]
+
+		line := [][2]float64{}
+		for _, i := range thin(len(series.Values)) {
+			line = append(line, [2]float64{x(i), p.y(series.Values[i])})
+		}
+
+		c.polyline(line, color, 2)
+
+		// a line of one point has no length so mark where it is
+		if len(line) == 1 {
+			c.rect(line[0][0]-2, line[0][1]-2, 4, 4, color)
+		}
+
+		// the legend goes under the x axis labels
+		legendX := p.left + float64(s)*180
+		legendY := float64(HEIGHT - 16)
+		c.rect(legendX, legendY-9, 12, 10, color)
+		c.text(legendX+18, legendY, series.Name, "start", 12, TEXT_COLOR)
+	}
+}
+
+func (chart BarChart) draw(c canvas) {
+	drawTitle(c, chart.Title)
+
+	if len(chart.Values) == 0 {
+		return
+	}
+
+	if chart.Horizontal {
+		chart.drawHorizontal(c)
+		return
+	}
+
+	// bars always start at zero
+	p := newPlot(append([]float64{0}, chart.Values...))
+	p.drawYAxis(c)
+
+	slot := p.width / float64(len(chart.Values))
+	for i, value := range chart.Values {
+		top := p.y(math.Max(value, 0))
+		c.rect(p.left+float64(i)*slot+slot*0.1, top, math.Max(slot*0.8, 1), math.Abs(p.y(value)-p.y(0)), COLORS[0])
+	}
+
+	for _, i := range xTicks(len(chart.Labels)) {
+		c.text(p.left+(float64(i)+0.5)*slot, p.top+p.height+18, chart.Labels[i], "middle", 11, TEXT_COLOR)
+	}
+}
+
+// drawHorizontal draws one bar per row with the label on the left and the value at the end of the bar
+func (chart BarChart) drawHorizontal(c canvas) {
+	left := 300.0
+	width := WIDTH - left - 100
+	top := float64(MARGIN_TOP)
+	row := math.Min((HEIGHT-top-10)/float64(len(chart.Values)), 30)
+
+	_, max := bounds(append([]float64{0}, chart.Values...))
+
+	for i, value := range chart.Values {
+		y := top + float64(i)*row
+		length := math.Max(value, 0) / max * width
+
+		label := ""
+		if i < len(chart.Labels) {
+			label = chart.Labels[i]
+		}
+
+		c.text(left-6, y+row*0.5+4, label, "end", 11, TEXT_COLOR)
+		c.rect(left, y+row*0.15, math.Max(length, 1), row*0.7, COLORS[0])
+		c.text(left+length+6, y+row*0.5+4, Humanize(value), "start", 11, TEXT_COLOR)
+	}
+}
+
+// xTicks returns the indexes of the labels to write under the x axis, evenly spread from the first to the last
+func xTicks(length int) []int {
+	if length <= X_TICKS {
+		indexes := make([]int, length)
+		for i := range indexes {
+			indexes[i] = i
+		}
+
+		return indexes
+	}
+
+	indexes := make([]int, X_TICKS)
+	for tick := range indexes {
+		indexes[tick] = int(math.Round(float64(tick) * float64(length-1) / (X_TICKS - 1)))
+	}
+
+	return indexes
+}
+
+// bounds returns the smallest and largest value with a little room so flat lines are still drawn
+func bounds(values []float64) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 1
+	}
+
+	min, max := math.Inf(1), math.Inf(-1)
+	for _, value := range values {
+		min = math.Min(min, value)
+		max = math.Max(max, value)
+	}
+
+	// differences this small are rounding and not worth showing
+	if max-min <= math.Max(math.Abs(min), math.Abs(max))*1e-9 {
+		if min == 0 {
+			return 0, 1
+		}
+
+		return min - math.Abs(min)*0.1, max + math.Abs(max)*0.1
+	}
+
+	return min, max
+}
+
+// thin returns the indexes of the points to draw, every point unless there are more than MAX_POINTS
+func thin(length int) []int {
+	step := 1
+	if length > MAX_POINTS {
+		step = int(math.Ceil(float64(length) / MAX_POINTS))
+	}
+
+	indexes := []int{}
+	for i := 0; i < length; i += step {
+		indexes = append(indexes, i)
+	}
+
+	// always end on the last point so the line reaches the right side
+	if length > 0 && indexes[len(indexes)-1] != length-1 {
+		indexes = append(indexes, length-1)
+	}
+
+	return indexes
+}
+
+// Humanize writes large numbers with a K, M, B or T suffix and small ones with a few decimals
+func Humanize(value float64) string {
+	return humanize(value, 0)
+}
+
+// humanize is Humanize with enough decimals to tell apart numbers that are step apart
+func humanize(value float64, step float64) string {
+	size, suffix := 1.0, ""
+	for _, unit := range []struct {
+		size   float64
+		suffix string
+	}{{1e12, "T"}, {1e9, "B"}, {1e6, "M"}, {1e3, "K"}} {
+		// numbers that round up to the next unit are written in it, 999.999B is 1T
+		if math.Abs(value) >= unit.size*0.9995 {
+			size, suffix = unit.size, unit.suffix
+			break
+		}
+	}
+
+	decimals := 2
+	if size == 1 && math.Abs(value) < 1 {
+		decimals = 4
+	}
+
+	if step > 0 {
+		decimals = int(math.Max(float64(decimals), math.Min(math.Ceil(-math.Log10(step/size)), 8)))
+	}
+
+	str := strconv.FormatFloat(value/size, 'f', decimals, 64)
+	if strings.Contains(str, ".") {
+		str = strings.TrimSuffix(strings.TrimRight(str, "0"), ".")
+	}
+
+	if str == "-0" {
+		str = "0"
+	}
+
+	return str + suffix
+}
@@ -0,0 +1,85 @@
+package chart
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHumanize(t *testing.T) {
+	assert.Equal(t, "0", Humanize(0))
+	assert.Equal(t, "12.5", Humanize(12.5))
+	assert.Equal(t, "0.13", Humanize(0.13))
+	assert.Equal(t, "1.5K", Humanize(1500))
+	assert.Equal(t, "11.58T", Humanize(11582258000000))
+	assert.Equal(t, "-2M", Humanize(-2000000))
+	assert.Equal(t, "1T", Humanize(999999999999))
+
+	// tick labels get the decimals they need to be different from each other
+	assert.Equal(t, "1.000007T", humanize(1000006800000, 6800000))
+	assert.Equal(t, "13", humanize(13, 0.5))
+}
+
+func TestSVG(t *testing.T) {
+	values := make([]float64, 5000)
+	labels := make([]string, 5000)
+	for i := range values {
+		values[i] = float64(i * i)
+		labels[i] = "day <" + strings.Repeat("x", i%3) + ">"
+	}
+
+	for _, chart := range []Chart{
+		LineChart{Title: "Supply & Inflation", Labels: labels, Series: []Series{{Name: "Supply", Values: values}, {Name: "Flat", Values: make([]float64, 10)}}},
+		BarChart{Title: "Unlocks", Labels: labels[:20], Values: values[:20]},
+		BarChart{Title: "Holders", Labels: labels[:3], Values: values[:3], Horizontal: true},
+		BarChart{Title: "Empty"},
+	} {
+		svg := SVG(chart)
+
+		// the svg has to be well formed so it can be opened on its own and put into html
+		decoder := xml.NewDecoder(strings.NewReader(svg))
+		for {
+			_, err := decoder.Token()
+			if err != nil {
+				assert.Equal(t, "EOF", err.Error())
+				break
+			}
+		}
+
+		assert.True(t, strings.HasPrefix(svg, "<svg "))
+		assert.NotContains(t, svg, "NaN")
+		assert.NotContains(t, svg, "+Inf")
+	}
+
+	// long lines are thinned out
+	svg := SVG(LineChart{Labels: labels, Series: []Series{{Name: "Supply", Values: values}}})
+	points := strings.Split(strings.Split(svg, `<polyline points="`)[1], `"`)[0]
+	assert.LessOrEqual(t, len(strings.Fields(points)), MAX_POINTS+1)
+}
+
+func TestPNG(t *testing.T) {
+	assert.Equal(t, color.RGBA{R: 0x1f, G: 0x77, B: 0xb4, A: 0xff}, parseColor("#1f77b4"))
+	assert.Equal(t, color.Black, parseColor("blue"))
+
+	var buf bytes.Buffer
+	err := WritePNG(&buf, BarChart{Title: "Unlocks", Labels: []string{"a", "b"}, Values: []float64{1, 2}})
+	assert.Nil(t, err)
+
+	image, err := png.Decode(&buf)
+	assert.Nil(t, err)
+	assert.Equal(t, WIDTH, image.Bounds().Dx())
+	assert.Equal(t, HEIGHT, image.Bounds().Dy())
+
+	// the background is white and the second bar reaches the top of the plot
+	r, g, b, _ := image.At(2, 2).RGBA()
+	assert.Equal(t, []uint32{0xffff, 0xffff, 0xffff}, []uint32{r, g, b})
+
+	slot := float64(WIDTH-MARGIN_LEFT-MARGIN_RIGHT) / 2
+	r, g, b, _ = image.At(int(MARGIN_LEFT+slot*1.5), MARGIN_TOP+2).RGBA()
+	assert.Equal(t, []uint32{0x1f1f, 0x7777, 0xb4b4}, []uint32{r, g, b})
+}
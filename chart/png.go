@@ -0,0 +1,107 @@
+package chart
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+	"strconv"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// pngCanvas draws the chart onto an image. Text always uses the same small fixed font so the
+// size is ignored.
+type pngCanvas struct {
+	image *image.RGBA
+}
+
+func (p *pngCanvas) line(x1, y1, x2, y2 float64, color string, width float64) {
+	c := parseColor(color)
+
+	// step one pixel at a time along the longest side and stamp a square of the line width
+	steps := math.Max(math.Abs(x2-x1), math.Abs(y2-y1))
+	if steps < 1 {
+		steps = 1
+	}
+
+	half := math.Max(width, 1) / 2
+	for step := 0.0; step <= steps; step++ {
+		x := x1 + (x2-x1)*step/steps
+		y := y1 + (y2-y1)*step/steps
+
+		p.fill(x-half, y-half, x+half, y+half, c)
+	}
+}
+
+func (p *pngCanvas) polyline(points [][2]float64, color string, width float64) {
+	for i := 1; i < len(points); i++ {
+		p.line(points[i-1][0], points[i-1][1], points[i][0], points[i][1], color, width)
+	}
+}
+
+func (p *pngCanvas) rect(x, y, width, height float64, color string) {
+	p.fill(x, y, x+width, y+height, parseColor(color))
+}
+
+func (p *pngCanvas) text(x, y float64, str string, anchor string, size float64, color string) {
+	drawer := font.Drawer{Dst: p.image, Src: image.NewUniform(parseColor(color)), Face: basicfont.Face7x13}
+
+	width := float64(drawer.MeasureString(str).Round())
+	switch anchor {
+	case "middle":
+		x -= width / 2
+	case "end":
+		x -= width
+	}
+
+	drawer.Dot = fixed.P(int(math.Round(x)), int(math.Round(y)))
+	drawer.DrawString(str)
+}
+
+// fill colors the pixels between the two corners, at least one pixel
+func (p *pngCanvas) fill(x1, y1, x2, y2 float64, c color.Color) {
+	r := image.Rect(int(math.Round(x1)), int(math.Round(y1)), int(math.Round(x2)), int(math.Round(y2)))
+	if r.Dx() == 0 {
+		r.Max.X++
+	}
+
+	if r.Dy() == 0 {
+		r.Max.Y++
+	}
+
+	draw.Draw(p.image, r, image.NewUniform(c), image.Point{}, draw.Src)
+}
+
+// parseColor reads colors written as #rrggbb, anything else is black
+func parseColor(str string) color.Color {
+	if len(str) != 7 || str[0] != '#' {
+		return color.Black
+	}
+
+	rgb, err := strconv.ParseUint(str[1:], 16, 32)
+	if err != nil {
+		return color.Black
+	}
+
+	return color.RGBA{R: uint8(rgb >> 16), G: uint8(rgb >> 8), B: uint8(rgb), A: 0xff}
+}
+
+// PNG draws the chart as an image
+func PNG(chart Chart) image.Image {
+	p := &pngCanvas{image: image.NewRGBA(image.Rect(0, 0, WIDTH, HEIGHT))}
+	draw.Draw(p.image, p.image.Bounds(), image.White, image.Point{}, draw.Src)
+
+	chart.draw(p)
+
+	return p.image
+}
+
+// WritePNG writes the chart to writer as png
+func WritePNG(writer io.Writer, chart Chart) error {
+	return png.Encode(writer, PNG(chart))
+}
@@ -0,0 +1,49 @@
+package chart
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+)
+
+// svgCanvas writes the chart as svg elements
+type svgCanvas struct {
+	builder strings.Builder
+}
+
+func (s *svgCanvas) line(x1, y1, x2, y2 float64, color string, width float64) {
+	fmt.Fprintf(&s.builder, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s" stroke-width="%g"/>`+"\n", x1, y1, x2, y2, color, width)
+}
+
+func (s *svgCanvas) polyline(points [][2]float64, color string, width float64) {
+	coordinates := make([]string, len(points))
+	for i, point := range points {
+		coordinates[i] = fmt.Sprintf("%.1f,%.1f", point[0], point[1])
+	}
+
+	fmt.Fprintf(&s.builder, `<polyline points="%s" fill="none" stroke="%s" stroke-width="%g"/>`+"\n", strings.Join(coordinates, " "), color, width)
+}
+
+func (s *svgCanvas) rect(x, y, width, height float64, color string) {
+	fmt.Fprintf(&s.builder, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s"/>`+"\n", x, y, width, height, color)
+}
+
+func (s *svgCanvas) text(x, y float64, str string, anchor string, size float64, color string) {
+	fmt.Fprintf(&s.builder, `<text x="%.1f" y="%.1f" text-anchor="%s" font-size="%g" fill="%s">%s</text>`+"\n", x, y, anchor, size, color, html.EscapeString(str))
+}
+
+// SVG returns the chart as a standalone svg document that can also be put straight into html
+func SVG(chart Chart) string {
+	s := &svgCanvas{}
+	chart.draw(s)
+
+	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif">`+"\n"+
+		`<rect width="100%%" height="100%%" fill="#ffffff"/>`+"\n%s</svg>\n", WIDTH, HEIGHT, WIDTH, HEIGHT, s.builder.String())
+}
+
+// WriteSVG writes the chart to writer as svg
+func WriteSVG(writer io.Writer, chart Chart) error {
+	_, err := io.WriteString(writer, SVG(chart))
+	return err
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	chartModule "github.com/brianosaurus/challenge2/chart"
+	reportModule "github.com/brianosaurus/challenge2/report"
+)
+
+// WriteChart writes one chart of the report data to path as svg or png
+func WriteChart(path string, name string, format string, data reportModule.Data) error {
+	c, err := reportModule.Chart(name, data)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	switch format {
+	case "svg":
+		return chartModule.WriteSVG(file, c)
+	case "png":
+		return chartModule.WritePNG(file, c)
+	}
+
+	return fmt.Errorf("unknown chart format %q", format)
+}
+
+// runCharts writes each chart to its own image file, <chart>.<format> in the -out directory
+func runCharts(args []string) {
+	flags := flag.NewFlagSet("chart", flag.ExitOnError)
+	out := flags.String("out", ".", "the directory to write the charts to")
+	format := flags.String("format", "svg", "the image format: svg or png")
+	charts := flags.String("charts", "supply,inflation,unlocks", "the charts to draw: "+strings.Join(reportModule.CHARTS, ", "))
+	genesisFile := flags.String("genesis", "genesis.json", "the genesis file to analyze")
+	scenarioFile := flags.String("scenario", "", "a scenario file with the assumptions of the projection (the first scenario is used)")
+	flags.Parse(args)
+
+	if *format != "svg" && *format != "png" {
+		fmt.Printf("Unknown format %q\n", *format)
+		return
+	}
+
+	scenario, err := firstScenario(*scenarioFile)
+	if err != nil {
+		fmt.Println("Error reading scenario file:", err)
+		return
+	}
+
+	genesis, err := ReadGenesis(*genesisFile)
+	if err != nil {
+		fmt.Println("Error reading genesis file:", err)
+		return
+	}
+
+	data, err := NewReportData(genesis, scenario)
+	if err != nil {
+		fmt.Println("Error in scenario:", err)
+		return
+	}
+
+	err = os.MkdirAll(*out, 0755)
+	if err != nil {
+		fmt.Println("Error creating output directory:", err)
+		return
+	}
+
+	for _, name := range strings.Split(*charts, ",") {
+		name = strings.TrimSpace(name)
+		path := filepath.Join(*out, name+"."+*format)
+
+		err = WriteChart(path, name, *format, data)
+		if err != nil {
+			fmt.Println("Error writing chart:", err)
+			return
+		}
+
+		fmt.Println("Wrote", path)
+	}
+
+	fmt.Printf("\nDone\n")
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/brianosaurus/challenge2/projection"
+	scenarioModule "github.com/brianosaurus/challenge2/scenario"
+)
+
+// Input is a genesis file projected with the assumptions of a scenario
+type Input struct {
+	Name     string
+	Genesis  Genesis
+	Scenario scenarioModule.Scenario
+}
+
+// CompareInputs projects both inputs a day at a time over the same horizon, the longer of the two, and compares them.
+// The inputs need the same anchor so their rows are for the same days.
+func CompareInputs(a Input, b Input) (projection.Comparison, error) {
+	configs := []projection.Config{}
+	horizon := 0
+
+	for _, input := range []Input{a, b} {
+		input.Scenario.Granularity = projection.GRANULARITY_DAY
+
+		config, err := input.Scenario.Config(input.Genesis.AppState)
+		if err != nil {
+			return projection.Comparison{}, fmt.Errorf("%s: %w", input.Name, err)
+		}
+
+		input.Genesis.SetHeights(&config)
+
+		days := config.Days
+		if days <= 0 {
+			days = projection.LastUnlock(*config.VestingOnDays) + 1
+		}
+
+		if days > horizon {
+			horizon = days
+		}
+
+		configs = append(configs, config)
+	}
+
+	for i := range configs {
+		configs[i].Days = horizon
+	}
+
+	projections := []projection.Projection{projection.Run(configs[0]), projection.Run(configs[1])}
+
+	// rows line up by position, another anchor would compare different days
+	if err := comparable([]string{a.Name, b.Name}, projections); err != nil {
+		return projection.Comparison{}, err
+	}
+
+	return projection.Compare(projections[0], projections[1]), nil
+}
+
+// WriteDeltasCSV writes the metrics of both projections and their delta (B - A) for every day
+func WriteDeltasCSV(writer *csv.Writer, names [2]string, comparison projection.Comparison) {
+	defer writer.Flush()
+
+	writer.Write([]string{"# A", names[0]})
+	writer.Write([]string{"# B", names[1]})
+
+	header := []string{"Days Since Genesis Analyzed", "Date"}
+	for _, metric := range projection.METRICS {
+		header = append(header, metric.Name+" (A)", metric.Name+" (B)", metric.Name+" Delta")
+	}
+
+	err := writer.Write(header)
+	if err != nil {
+		fmt.Println("Error writing to csv")
+		return
+	}
+
+	for row := 0; row < comparison.Rows; row++ {
+		day := comparison.A.Rows[row]
+		csvStr := []string{comparison.A.DayColumn(row), comparison.A.Date(day).Format("2006-01-02")}
+
+		for _, metric := range projection.METRICS {
+			csvStr = append(csvStr, metricString(metric, metric.Value(day)), metricString(metric, metric.Value(comparison.B.Rows[row])),
+				metricString(metric, comparison.Delta(metric, row)))
+		}
+
+		writer.Write(csvStr)
+	}
+}
+
+// WriteDeltaSummary writes the summary statistics of every metric as an aligned table
+func WriteDeltaSummary(writer io.Writer, names [2]string, comparison projection.Comparison) error {
+	fmt.Fprintf(writer, "A: %s\nB: %s\n\n", names[0], names[1])
+
+	table := tabwriter.NewWriter(writer, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(table, "Metric\tFinal A\tFinal B\tFinal Delta\tFinal Delta %\tMean Delta\tMax Delta\tMax Delta Day\t")
+
+	for _, stats := range comparison.Stats {
+		metric := stats.Metric
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%d\t\n", metric.Name, tableString(metric, stats.FinalA),
+			tableString(metric, stats.FinalB), tableString(metric, stats.FinalDelta), percentString(stats.RelativeDelta()),
+			tableString(metric, stats.MeanDelta), tableString(metric, stats.MaxDelta), stats.MaxDeltaDay)
+	}
+
+	return table.Flush()
+}
+
+// runCompare overlays the projections of two genesis drafts, or of one genesis under two scenarios
+func runCompare(args []string) {
+	flags := flag.NewFlagSet("compare", flag.ExitOnError)
+	genesisA := flags.String("a", "genesis.json", "the first genesis file")
+	genesisB := flags.String("b", "", "the second genesis file (defaults to -a)")
+	scenarioA := flags.String("scenario-a", "", "a scenario file with the assumptions for the first genesis (the first scenario is used)")
+	scenarioB := flags.String("scenario-b", "", "a scenario file with the assumptions for the second genesis (defaults to -scenario-a)")
+	csvStr := flags.String("csv", "projection_deltas.csv", "the csv file to output the daily deltas to")
+	flags.Parse(args)
+
+	if *genesisB == "" {
+		*genesisB = *genesisA
+	}
+
+	if *scenarioB == "" {
+		*scenarioB = *scenarioA
+	}
+
+	if *genesisA == *genesisB && *scenarioA == *scenarioB {
+		fmt.Println("Nothing to compare, give a second genesis with -b or a second scenario with -scenario-b")
+		return
+	}
+
+	inputs := []Input{}
+	for _, paths := range [][2]string{{*genesisA, *scenarioA}, {*genesisB, *scenarioB}} {
+		scenario, err := firstScenario(paths[1])
+		if err != nil {
+			fmt.Println("Error reading scenario file:", err)
+			return
+		}
+
+		genesis, err := ReadGenesis(paths[0])
+		if err != nil {
+			fmt.Println("Error reading genesis file:", err)
+			return
+		}
+
+		name := paths[0]
+		if scenario.Name != "" {
+			name += " (" + scenario.Name + ")"
+		}
+
+		inputs = append(inputs, Input{Name: name, Genesis: genesis, Scenario: scenario})
+	}
+
+	comparison, err := CompareInputs(inputs[0], inputs[1])
+	if err != nil {
+		fmt.Println("Error in scenario:", err)
+		return
+	}
+
+	names := [2]string{inputs[0].Name, inputs[1].Name}
+
+	if err = WriteDeltaSummary(os.Stdout, names, comparison); err != nil {
+		fmt.Println("Error writing summary:", err)
+		return
+	}
+
+	file, err := os.Create(*csvStr)
+	if err != nil {
+		fmt.Println("Error creating csv file")
+		return
+	}
+	defer file.Close()
+
+	WriteDeltasCSV(csv.NewWriter(file), names, comparison)
+	fmt.Printf("\nDone\n")
+}
+
+func metricString(metric projection.Metric, value sdk.Dec) string {
+	if metric.Ratio {
+		return value.String()
+	}
+
+	return value.RoundInt().String()
+}
+
+// tableString is metricString with ratios in percent
+func tableString(metric projection.Metric, value sdk.Dec) string {
+	if metric.Ratio {
+		return percentString(value)
+	}
+
+	return value.RoundInt().String()
+}
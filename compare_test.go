@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	scenarioModule "github.com/brianosaurus/challenge2/scenario"
+)
+
+func TestCompareInputs(t *testing.T) {
+	genesis := Genesis{AppState: newTestAppState(t), ChainID: "umee-1", GenesisTime: time.Unix(1660582800, 0)}
+
+	a := Input{Name: "a", Genesis: genesis, Scenario: scenarioModule.Scenario{Anchor: "2022-11-22", Days: 30}}
+	b := Input{Name: "b", Genesis: genesis, Scenario: scenarioModule.Scenario{Anchor: "2022-11-22", Days: 10,
+		Mint: map[string]interface{}{"minter": map[string]interface{}{"inflation": "0.200000000000000000"}}}}
+
+	comparison, err := CompareInputs(a, b)
+	assert.Nil(t, err)
+
+	// both are projected over the longer horizon, a day at a time
+	assert.Equal(t, 31, comparison.Rows)
+	assert.Equal(t, 31, len(comparison.B.Rows))
+
+	// the same genesis starts out the same, b mints more from there on
+	supply := comparison.Stats[3]
+	assert.Equal(t, "Total Supply", supply.Metric.Name)
+	assert.True(t, comparison.Delta(supply.Metric, 0).IsZero())
+	assert.True(t, supply.FinalDelta.IsPositive())
+	assert.Equal(t, supply.FinalDelta, supply.MaxDelta)
+	assert.Equal(t, 29, supply.MaxDeltaDay)
+
+	var buffer bytes.Buffer
+	WriteDeltasCSV(csv.NewWriter(&buffer), [2]string{"a", "b"}, comparison)
+
+	// the # lines have fewer fields than the rows
+	reader := csv.NewReader(strings.NewReader(buffer.String()))
+	reader.FieldsPerRecord = -1
+
+	records, err := reader.ReadAll()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"# A", "a"}, records[0])
+	assert.Equal(t, []string{"# B", "b"}, records[1])
+	assert.Equal(t, []string{"Days Since Genesis Analyzed", "Date", "Inflation (A)", "Inflation (B)", "Inflation Delta"}, records[2][:5])
+	assert.Equal(t, 3+comparison.Rows, len(records))
+	assert.Equal(t, "2022-11-22", records[3][1])
+	assert.Equal(t, supply.FinalDelta.RoundInt().String(), records[len(records)-1][13])
+
+	buffer.Reset()
+	assert.Nil(t, WriteDeltaSummary(&buffer, [2]string{"a", "b"}, comparison))
+	assert.Contains(t, buffer.String(), "A: a\nB: b\n")
+	assert.Contains(t, buffer.String(), "Total Supply")
+}
+
+func TestCompareInputsAnchors(t *testing.T) {
+	genesis := Genesis{AppState: newTestAppState(t), ChainID: "umee-1", GenesisTime: time.Unix(1660582800, 0)}
+
+	a := Input{Name: "a", Genesis: genesis, Scenario: scenarioModule.Scenario{Anchor: "2022-11-22", Days: 10}}
+	b := Input{Name: "b", Genesis: genesis, Scenario: scenarioModule.Scenario{Anchor: "2022-12-01", Days: 10}}
+
+	_, err := CompareInputs(a, b)
+	assert.EqualError(t, err, "scenario b is anchored at 2022-12-01T00:00:00Z and a at 2022-11-22T00:00:00Z")
+}
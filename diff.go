@@ -0,0 +1,111 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	diffModule "github.com/brianosaurus/challenge2/diff"
+)
+
+// WriteDiff writes each section that changed as an aligned table
+func WriteDiff(writer io.Writer, d diffModule.Diff) error {
+	if d.Empty() {
+		_, err := fmt.Fprintln(writer, "No differences")
+		return err
+	}
+
+	sections := []struct {
+		title   string
+		changes []diffModule.Change
+	}{
+		{"Accounts", d.Accounts},
+		{"Vesting", d.Vesting},
+		{"Balances", d.Balances},
+		{"Validators", d.Validators},
+		{"Mint Params", d.MintParams},
+	}
+
+	first := true
+	for _, section := range sections {
+		if len(section.changes) == 0 {
+			continue
+		}
+
+		if !first {
+			fmt.Fprintln(writer)
+		}
+		first = false
+
+		fmt.Fprintf(writer, "%s (%d)\n", section.title, len(section.changes))
+
+		table := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(table, "Kind\tKey\tField\tOld\tNew\tDelta")
+
+		for _, change := range section.changes {
+			fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\t%s\n", change.Kind, change.Key, change.Field, change.Old, change.New, change.Delta)
+		}
+
+		if err := table.Flush(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WriteDiffJSON writes the diff as one json document
+func WriteDiffJSON(writer io.Writer, d diffModule.Diff) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(d)
+}
+
+// runDiff prints what changed between two genesis files, e.g. two drafts of a launch genesis
+func runDiff(args []string) {
+	flags := flag.NewFlagSet("diff", flag.ExitOnError)
+	format := flags.String("format", "table", "the output format: table or json")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: genesisAnalyzer diff [flags] old.json new.json")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if *format != "table" && *format != "json" {
+		fmt.Printf("Unknown format %q\n", *format)
+		return
+	}
+
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return
+	}
+
+	old, err := ReadGenesis(flags.Arg(0))
+	if err != nil {
+		fmt.Println("Error reading genesis file:", err)
+		return
+	}
+
+	new, err := ReadGenesis(flags.Arg(1))
+	if err != nil {
+		fmt.Println("Error reading genesis file:", err)
+		return
+	}
+
+	d := diffModule.Compare(old.AppState, new.AppState)
+
+	if *format == "json" {
+		err = WriteDiffJSON(os.Stdout, d)
+	} else {
+		err = WriteDiff(os.Stdout, d)
+	}
+
+	if err != nil {
+		fmt.Println("Error writing diff:", err)
+	}
+}
@@ -0,0 +1,240 @@
+package diff
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	stakingModule "github.com/brianosaurus/challenge2/staking"
+	vestingModule "github.com/brianosaurus/challenge2/vesting"
+)
+
+const (
+	ADDED   = "added"
+	REMOVED = "removed"
+	CHANGED = "changed"
+)
+
+// Change is one difference between two genesis files. Key is the address, operator address or param that
+// changed and Field what changed about it, added and removed keys have no Field. Delta is only set for balances.
+type Change struct {
+	Kind  string `json:"kind"`
+	Key   string `json:"key"`
+	Field string `json:"field,omitempty"`
+	Old   string `json:"old,omitempty"`
+	New   string `json:"new,omitempty"`
+	Delta string `json:"delta,omitempty"`
+}
+
+// Diff is everything that changed tokenomically between two genesis files, each section sorted by key
+type Diff struct {
+	Accounts   []Change `json:"accounts"`
+	Vesting    []Change `json:"vesting"`
+	Balances   []Change `json:"balances"`
+	Validators []Change `json:"validators"`
+	MintParams []Change `json:"mint_params"`
+}
+
+// Empty is true when nothing changed
+func (d Diff) Empty() bool {
+	return len(d.Accounts)+len(d.Vesting)+len(d.Balances)+len(d.Validators)+len(d.MintParams) == 0
+}
+
+// Compare returns what changed from the old app state to the new one
+func Compare(old map[string]interface{}, new map[string]interface{}) Diff {
+	return Diff{
+		Accounts:   compare(accounts(old), accounts(new)),
+		Vesting:    compare(vesting(old), vesting(new)),
+		Balances:   compareBalances(balances(old), balances(new)),
+		Validators: compare(validators(old), validators(new)),
+		MintParams: compareParams(mintParams(old), mintParams(new)),
+	}
+}
+
+// compare finds the keys that were added or removed and the fields of the other keys that changed
+func compare(old map[string]map[string]string, new map[string]map[string]string) []Change {
+	changes := []Change{}
+
+	for _, key := range keys(old, new) {
+		oldFields, inOld := old[key]
+		newFields, inNew := new[key]
+
+		switch {
+		case !inOld:
+			changes = append(changes, Change{Kind: ADDED, Key: key, New: summary(newFields)})
+		case !inNew:
+			changes = append(changes, Change{Kind: REMOVED, Key: key, Old: summary(oldFields)})
+		default:
+			for _, field := range keys(oldFields, newFields) {
+				if oldFields[field] != newFields[field] {
+					changes = append(changes, Change{Kind: CHANGED, Key: key, Field: field, Old: oldFields[field], New: newFields[field]})
+				}
+			}
+		}
+	}
+
+	return changes
+}
+
+// compareBalances is compare for amounts by address and denom, with the delta of each
+func compareBalances(old map[string]sdk.Int, new map[string]sdk.Int) []Change {
+	changes := []Change{}
+
+	for _, key := range keys(old, new) {
+		oldAmount, inOld := old[key]
+		newAmount, inNew := new[key]
+
+		switch {
+		case !inOld:
+			changes = append(changes, Change{Kind: ADDED, Key: key, New: newAmount.String(), Delta: newAmount.String()})
+		case !inNew:
+			changes = append(changes, Change{Kind: REMOVED, Key: key, Old: oldAmount.String(), Delta: oldAmount.Neg().String()})
+		case !oldAmount.Equal(newAmount):
+			changes = append(changes, Change{Kind: CHANGED, Key: key, Old: oldAmount.String(), New: newAmount.String(),
+				Delta: newAmount.Sub(oldAmount).String()})
+		}
+	}
+
+	return changes
+}
+
+// compareParams is compare for the params of a single module
+func compareParams(old map[string]string, new map[string]string) []Change {
+	changes := []Change{}
+
+	for _, key := range keys(old, new) {
+		oldValue, inOld := old[key]
+		newValue, inNew := new[key]
+
+		switch {
+		case !inOld:
+			changes = append(changes, Change{Kind: ADDED, Key: key, New: newValue})
+		case !inNew:
+			changes = append(changes, Change{Kind: REMOVED, Key: key, Old: oldValue})
+		case oldValue != newValue:
+			changes = append(changes, Change{Kind: CHANGED, Key: key, Old: oldValue, New: newValue})
+		}
+	}
+
+	return changes
+}
+
+func accounts(appState map[string]interface{}) map[string]map[string]string {
+	byAddress := make(map[string]map[string]string)
+
+	for _, account := range vestingModule.GetAuthAccounts(appState) {
+		byAddress[account.Address] = map[string]string{"type": account.Type, "account_number": account.AccountNumber}
+	}
+
+	return byAddress
+}
+
+func vesting(appState map[string]interface{}) map[string]map[string]string {
+	byAddress := make(map[string]map[string]string)
+
+	continuousAccounts, delayedAccounts := vestingModule.GetVestingAccounts(appState)
+	for _, account := range vestingModule.GetAccounts(continuousAccounts, delayedAccounts) {
+		fields := map[string]string{
+			"type":             account.Type,
+			"original_vesting": account.OriginalVesting.String() + account.Denom,
+			"end_time":         timestamp(account.EndTime),
+		}
+
+		if account.Type == vestingModule.ACCOUNT_CONTINUOUS {
+			fields["start_time"] = timestamp(account.StartTime)
+		}
+
+		byAddress[account.Address] = fields
+	}
+
+	return byAddress
+}
+
+func balances(appState map[string]interface{}) map[string]sdk.Int {
+	byAddress := make(map[string]sdk.Int)
+
+	for _, balance := range vestingModule.GetBalances(appState) {
+		key := balance.Address + " " + balance.Denom
+		if amount, ok := byAddress[key]; ok {
+			byAddress[key] = amount.Add(balance.Amount)
+		} else {
+			byAddress[key] = balance.Amount
+		}
+	}
+
+	return byAddress
+}
+
+func validators(appState map[string]interface{}) map[string]map[string]string {
+	byOperator := make(map[string]map[string]string)
+
+	for _, validator := range stakingModule.GetValidators(appState) {
+		byOperator[validator.OperatorAddress] = map[string]string{
+			"moniker":         validator.Moniker,
+			"tokens":          validator.Tokens.RoundInt().String(),
+			"commission_rate": validator.CommissionRate.String(),
+			"max_rate":        validator.MaxRate.String(),
+			"max_change_rate": validator.MaxChangeRate.String(),
+			"jailed":          fmt.Sprint(validator.Jailed),
+			"status":          validator.Status,
+		}
+	}
+
+	return byOperator
+}
+
+func mintParams(appState map[string]interface{}) map[string]string {
+	params := make(map[string]string)
+
+	mint, _ := appState["mint"].(map[string]interface{})
+	raw, _ := mint["params"].(map[string]interface{})
+	for key, value := range raw {
+		params[key] = fmt.Sprint(value)
+	}
+
+	return params
+}
+
+// summary writes the fields of an added or removed key on one line
+func summary(fields map[string]string) string {
+	str := ""
+
+	for _, field := range keys(fields, nil) {
+		if fields[field] == "" {
+			continue
+		}
+
+		if str != "" {
+			str += " "
+		}
+
+		str += field + "=" + fields[field]
+	}
+
+	return str
+}
+
+// keys returns the keys of both maps in order
+func keys[T any](old map[string]T, new map[string]T) []string {
+	all := []string{}
+
+	for key := range old {
+		all = append(all, key)
+	}
+
+	for key := range new {
+		if _, ok := old[key]; !ok {
+			all = append(all, key)
+		}
+	}
+
+	sort.Strings(all)
+
+	return all
+}
+
+func timestamp(unix int64) string {
+	return time.Unix(unix, 0).UTC().Format(time.RFC3339)
+}
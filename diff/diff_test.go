@@ -0,0 +1,261 @@
+package diff
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	OLD_APP_STATE =
+`{
+	"auth": {
+		"accounts": [
+			{
+				"@type": "/cosmos.vesting.v1beta1.DelayedVestingAccount",
+				"base_vesting_account": {
+					"base_account": {
+						"address": "umee1agzky2ak6xs5vve3c2wzjtqdq7fwadcgj2mxf9",
+						"pub_key": null,
+						"account_number": "0",
+						"sequence": "0"
+					},
+					"original_vesting": [
+						{
+							"denom": "uumee",
+							"amount": "309282000000"
+						}
+					],
+					"delegated_free": [],
+					"delegated_vesting": [],
+					"end_time": "1676480400"
+				}
+			},
+			{
+				"@type": "/cosmos.auth.v1beta1.BaseAccount",
+				"address": "umee1qqq8fdsrcsz4jnlvrcqa6ds2ruflgrgeguyeh0",
+				"pub_key": null,
+				"account_number": "1",
+				"sequence": "0"
+			}
+		]
+	},
+	"bank": {
+		"balances": [
+			{
+				"address": "umee1agzky2ak6xs5vve3c2wzjtqdq7fwadcgj2mxf9",
+				"coins": [
+					{
+						"denom": "uumee",
+						"amount": "309282000000"
+					}
+				]
+			},
+			{
+				"address": "umee1qqq8fdsrcsz4jnlvrcqa6ds2ruflgrgeguyeh0",
+				"coins": [
+					{
+						"denom": "uumee",
+						"amount": "8333000000"
+					}
+				]
+			}
+		]
+	},
+	"staking": {
+		"validators": [
+			{
+				"operator_address": "umeevaloper1n3mhyp9fvcmuu8l0q8qvjy07x0rql8q4d0h0la",
+				"jailed": false,
+				"status": "BOND_STATUS_BONDED",
+				"tokens": "1000000",
+				"description": {
+					"moniker": "0base.vc"
+				},
+				"commission": {
+					"commission_rates": {
+						"rate": "0.050000000000000000",
+						"max_rate": "0.100000000000000000",
+						"max_change_rate": "0.010000000000000000"
+					}
+				}
+			}
+		]
+	},
+	"mint": {
+		"params": {
+			"mint_denom": "uumee",
+			"inflation_max": "0.200000000000000000",
+			"inflation_min": "0.070000000000000000"
+		}
+	}
+}`
+
+	NEW_APP_STATE =
+`{
+	"auth": {
+		"accounts": [
+			{
+				"@type": "/cosmos.vesting.v1beta1.DelayedVestingAccount",
+				"base_vesting_account": {
+					"base_account": {
+						"address": "umee1agzky2ak6xs5vve3c2wzjtqdq7fwadcgj2mxf9",
+						"pub_key": null,
+						"account_number": "0",
+						"sequence": "0"
+					},
+					"original_vesting": [
+						{
+							"denom": "uumee",
+							"amount": "309282000000"
+						}
+					],
+					"delegated_free": [],
+					"delegated_vesting": [],
+					"end_time": "1684256400"
+				}
+			},
+			{
+				"@type": "/cosmos.auth.v1beta1.BaseAccount",
+				"address": "umee1qqqk0gxu4he52m0t2w6f6vfag6uvyaegprmj58",
+				"pub_key": null,
+				"account_number": "1",
+				"sequence": "0"
+			}
+		]
+	},
+	"bank": {
+		"balances": [
+			{
+				"address": "umee1agzky2ak6xs5vve3c2wzjtqdq7fwadcgj2mxf9",
+				"coins": [
+					{
+						"denom": "uumee",
+						"amount": "309282000000"
+					}
+				]
+			},
+			{
+				"address": "umee1qqqk0gxu4he52m0t2w6f6vfag6uvyaegprmj58",
+				"coins": [
+					{
+						"denom": "uumee",
+						"amount": "7500000000"
+					}
+				]
+			}
+		]
+	},
+	"staking": {
+		"validators": [
+			{
+				"operator_address": "umeevaloper1n3mhyp9fvcmuu8l0q8qvjy07x0rql8q4d0h0la",
+				"jailed": false,
+				"status": "BOND_STATUS_BONDED",
+				"tokens": "1000000",
+				"description": {
+					"moniker": "0base.vc"
+				},
+				"commission": {
+					"commission_rates": {
+						"rate": "0.100000000000000000",
+						"max_rate": "0.100000000000000000",
+						"max_change_rate": "0.010000000000000000"
+					}
+				}
+			},
+			{
+				"operator_address": "umeevaloper1qqqk0gxu4he52m0t2w6f6vfag6uvyaeg6k6sjv",
+				"jailed": false,
+				"status": "BOND_STATUS_BONDED",
+				"tokens": "2000000",
+				"description": {
+					"moniker": "Figment"
+				},
+				"commission": {
+					"commission_rates": {
+						"rate": "0.050000000000000000",
+						"max_rate": "0.200000000000000000",
+						"max_change_rate": "0.010000000000000000"
+					}
+				}
+			}
+		]
+	},
+	"mint": {
+		"params": {
+			"mint_denom": "uumee",
+			"inflation_max": "0.250000000000000000",
+			"inflation_min": "0.070000000000000000",
+			"goal_bonded": "0.670000000000000000"
+		}
+	}
+}`
+)
+
+func decode(t *testing.T, raw string) map[string]interface{} {
+	var appState = make(map[string]interface{})
+
+	err := json.Unmarshal([]byte(raw), &appState)
+	if err != nil {
+		t.Log("Error decoding json")
+		t.FailNow()
+	}
+
+	return appState
+}
+
+func TestCompare(t *testing.T) {
+	d := Compare(decode(t, OLD_APP_STATE), decode(t, NEW_APP_STATE))
+
+	assert.False(t, d.Empty())
+
+	assert.Equal(t, []Change{
+		{Kind: REMOVED, Key: "umee1qqq8fdsrcsz4jnlvrcqa6ds2ruflgrgeguyeh0", Old: "account_number=1 type=/cosmos.auth.v1beta1.BaseAccount"},
+		{Kind: ADDED, Key: "umee1qqqk0gxu4he52m0t2w6f6vfag6uvyaegprmj58", New: "account_number=1 type=/cosmos.auth.v1beta1.BaseAccount"},
+	}, d.Accounts)
+
+	assert.Equal(t, []Change{
+		{Kind: CHANGED, Key: "umee1agzky2ak6xs5vve3c2wzjtqdq7fwadcgj2mxf9", Field: "end_time", Old: "2023-02-15T17:00:00Z", New: "2023-05-16T17:00:00Z"},
+	}, d.Vesting)
+
+	assert.Equal(t, []Change{
+		{Kind: REMOVED, Key: "umee1qqq8fdsrcsz4jnlvrcqa6ds2ruflgrgeguyeh0 uumee", Old: "8333000000", Delta: "-8333000000"},
+		{Kind: ADDED, Key: "umee1qqqk0gxu4he52m0t2w6f6vfag6uvyaegprmj58 uumee", New: "7500000000", Delta: "7500000000"},
+	}, d.Balances)
+
+	assert.Len(t, d.Validators, 2)
+	assert.Equal(t, Change{Kind: CHANGED, Key: "umeevaloper1n3mhyp9fvcmuu8l0q8qvjy07x0rql8q4d0h0la", Field: "commission_rate",
+		Old: "0.050000000000000000", New: "0.100000000000000000"}, d.Validators[0])
+	assert.Equal(t, ADDED, d.Validators[1].Kind)
+	assert.Equal(t, "umeevaloper1qqqk0gxu4he52m0t2w6f6vfag6uvyaeg6k6sjv", d.Validators[1].Key)
+	assert.Contains(t, d.Validators[1].New, "moniker=Figment")
+	assert.Contains(t, d.Validators[1].New, "tokens=2000000")
+
+	assert.Equal(t, []Change{
+		{Kind: ADDED, Key: "goal_bonded", New: "0.670000000000000000"},
+		{Kind: CHANGED, Key: "inflation_max", Old: "0.200000000000000000", New: "0.250000000000000000"},
+	}, d.MintParams)
+}
+
+func TestCompareSame(t *testing.T) {
+	d := Compare(decode(t, OLD_APP_STATE), decode(t, OLD_APP_STATE))
+
+	assert.True(t, d.Empty())
+	assert.Empty(t, d.Accounts)
+	assert.Empty(t, d.MintParams)
+}
+
+func TestCompareBalances(t *testing.T) {
+	old := decode(t, OLD_APP_STATE)
+	new := decode(t, OLD_APP_STATE)
+
+	balances := new["bank"].(map[string]interface{})["balances"].([]interface{})
+	coins := balances[1].(map[string]interface{})["coins"].([]interface{})
+	coins[0].(map[string]interface{})["amount"] = "8000000000"
+
+	assert.Equal(t, []Change{
+		{Kind: CHANGED, Key: "umee1qqq8fdsrcsz4jnlvrcqa6ds2ruflgrgeguyeh0 uumee", Old: "8333000000", New: "8000000000", Delta: "-333000000"},
+	}, Compare(old, new).Balances)
+}
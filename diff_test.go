@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	diffModule "github.com/brianosaurus/challenge2/diff"
+)
+
+func TestWriteDiff(t *testing.T) {
+	var buffer bytes.Buffer
+
+	assert.Nil(t, WriteDiff(&buffer, diffModule.Diff{}))
+	assert.Equal(t, "No differences\n", buffer.String())
+
+	d := diffModule.Diff{
+		Balances: []diffModule.Change{
+			{Kind: diffModule.CHANGED, Key: "umee1d uumee", Old: "10", New: "15", Delta: "5"},
+		},
+		MintParams: []diffModule.Change{
+			{Kind: diffModule.CHANGED, Key: "inflation_max", Old: "0.2", New: "0.25"},
+		},
+	}
+
+	buffer.Reset()
+	assert.Nil(t, WriteDiff(&buffer, d))
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	assert.Equal(t, "Balances (1)", lines[0])
+	assert.Equal(t, []string{"changed", "umee1d", "uumee", "10", "15", "5"}, strings.Fields(lines[2]))
+	assert.Equal(t, "", lines[3])
+	assert.Equal(t, "Mint Params (1)", lines[4])
+	assert.Equal(t, []string{"changed", "inflation_max", "0.2", "0.25"}, strings.Fields(lines[6]))
+
+	// sections without changes are left out
+	assert.NotContains(t, buffer.String(), "Accounts")
+}
+
+func TestWriteDiffJSON(t *testing.T) {
+	var buffer bytes.Buffer
+
+	appState := newTestAppState(t)
+	assert.Nil(t, WriteDiffJSON(&buffer, diffModule.Compare(appState, appState)))
+
+	var decoded map[string][]diffModule.Change
+	assert.Nil(t, json.Unmarshal(buffer.Bytes(), &decoded))
+
+	for _, section := range []string{"accounts", "vesting", "balances", "validators", "mint_params"} {
+		changes, ok := decoded[section]
+		assert.True(t, ok, section)
+		assert.Empty(t, changes)
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	parquetModule "github.com/brianosaurus/challenge2/parquet"
+	"github.com/brianosaurus/challenge2/projection"
+	scenarioModule "github.com/brianosaurus/challenge2/scenario"
+	sqliteModule "github.com/brianosaurus/challenge2/sqlite"
+	stakingModule "github.com/brianosaurus/challenge2/staking"
+	vestingModule "github.com/brianosaurus/challenge2/vesting"
+)
+
+// NewSQLiteData collects the accounts, balances, vesting accounts and validators of genesis along
+// with the projection made with the scenario
+func NewSQLiteData(genesis Genesis, scenario scenarioModule.Scenario, projected projection.Projection) sqliteModule.Data {
+	appState := scenario.AppState(genesis.AppState)
+	metadata := NewMetadata(genesis, scenario, projected)
+
+	continuousAccounts, delayedAccounts := vestingModule.GetVestingAccounts(appState)
+
+	data := sqliteModule.Data{
+		Metadata: map[string]string{
+			"chain_id":        metadata.ChainID,
+			"genesis_hash":    metadata.GenesisHash,
+			"anchor_time":     metadata.AnchorTime.Format(time.RFC3339),
+			"granularity":     metadata.Granularity,
+			"inflation_model": metadata.InflationModel,
+		},
+		Accounts:        vestingModule.GetAuthAccounts(appState),
+		Balances:        vestingModule.GetBalances(appState),
+		VestingAccounts: vestingModule.GetAccounts(continuousAccounts, delayedAccounts),
+		Validators:      stakingModule.GetValidators(appState),
+		Projection:      projected,
+	}
+
+	if metadata.GenesisTime != nil {
+		data.Metadata["genesis_time"] = metadata.GenesisTime.Format(time.RFC3339)
+	}
+
+	return data
+}
+
+// writeParquet writes the projection to out and the daily schedule of every vesting account to schedules
+func writeParquet(out string, schedules string, genesis Genesis, scenario scenarioModule.Scenario, projected projection.Projection) error {
+	file, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	err = parquetModule.WriteProjection(file, projected)
+	if err != nil {
+		return err
+	}
+
+	appState := scenario.AppState(genesis.AppState)
+	continuousAccounts, delayedAccounts := vestingModule.GetVestingAccounts(appState)
+
+	schedulesFile, err := os.Create(schedules)
+	if err != nil {
+		return err
+	}
+	defer schedulesFile.Close()
+
+	return parquetModule.WriteSchedules(schedulesFile, vestingModule.GetAccounts(continuousAccounts, delayedAccounts), projected.Anchor)
+}
+
+// runExport writes the genesis and the projection to a sqlite database that can be queried with sql,
+// or the projection and the vesting schedules to parquet files for data science tools
+func runExport(args []string) {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	format := flags.String("format", "sqlite", "the export format: sqlite or parquet")
+	out := flags.String("out", "", "the file to export to (defaults to genesis_analysis.db for sqlite and genesis_analysis.parquet for parquet)")
+	schedules := flags.String("schedules", "vesting_schedules.parquet", "the parquet file to export the vesting schedule of every account to")
+	genesisFile := flags.String("genesis", "genesis.json", "the genesis file to analyze")
+	scenarioFile := flags.String("scenario", "", "a scenario file with the assumptions of the projection (the first scenario is used)")
+	flags.Parse(args)
+
+	if *format != "sqlite" && *format != "parquet" {
+		fmt.Printf("Unknown format %q\n", *format)
+		return
+	}
+
+	if *out == "" {
+		*out = "genesis_analysis.db"
+		if *format == "parquet" {
+			*out = "genesis_analysis.parquet"
+		}
+	}
+
+	scenario, err := firstScenario(*scenarioFile)
+	if err != nil {
+		fmt.Println("Error reading scenario file:", err)
+		return
+	}
+
+	genesis, err := ReadGenesis(*genesisFile)
+	if err != nil {
+		fmt.Println("Error reading genesis file:", err)
+		return
+	}
+
+	config, err := scenario.Config(genesis.AppState)
+	if err != nil {
+		fmt.Println("Error in scenario:", err)
+		return
+	}
+
+	genesis.SetHeights(&config)
+
+	projected := projection.Run(config)
+
+	switch *format {
+	case "sqlite":
+		err = sqliteModule.Write(*out, NewSQLiteData(genesis, scenario, projected))
+	case "parquet":
+		err = writeParquet(*out, *schedules, genesis, scenario, projected)
+	}
+
+	if err != nil {
+		fmt.Println("Error exporting:", err)
+		return
+	}
+
+	fmt.Printf("\nDone\n")
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/brianosaurus/challenge2/projection"
+)
+
+// Genesis is a decoded genesis file along with what identifies it
+type Genesis struct {
+	AppState      map[string]interface{}
+	ChainID       string
+	GenesisTime   time.Time
+	InitialHeight int64
+	// sha256 of the genesis file, the same hash tendermint keeps for the genesis
+	Hash string
+}
+
+// ReadGenesis reads and decodes the genesis file
+func ReadGenesis(genesisFile string) (Genesis, error) {
+	var genesis Genesis
+
+	// read file with the io package
+	raw, err := os.ReadFile(genesisFile)
+	if err != nil {
+		return genesis, err
+	}
+
+	hash := sha256.Sum256(raw)
+	genesis.Hash = hex.EncodeToString(hash[:])
+
+	// create a map to store the data
+	var genesisJson map[string]interface{}
+
+	// decode the json file into the map
+	err = json.Unmarshal(raw, &genesisJson)
+	if err != nil {
+		return genesis, err
+	}
+
+	appState, ok := genesisJson["app_state"].(map[string]interface{})
+	if !ok {
+		return genesis, fmt.Errorf("%s has no app_state", genesisFile)
+	}
+
+	genesis.AppState = appState
+	genesis.ChainID, _ = genesisJson["chain_id"].(string)
+
+	if genesisTime, ok := genesisJson["genesis_time"].(string); ok {
+		genesis.GenesisTime, err = time.Parse(time.RFC3339Nano, genesisTime)
+		if err != nil {
+			return genesis, fmt.Errorf("invalid genesis_time: %w", err)
+		}
+	}
+
+	genesis.InitialHeight = 1
+	if initialHeight, ok := genesisJson["initial_height"].(string); ok {
+		genesis.InitialHeight, err = strconv.ParseInt(initialHeight, 10, 64)
+		if err != nil {
+			return genesis, fmt.Errorf("invalid initial_height: %w", err)
+		}
+	}
+
+	return genesis, nil
+}
+
+// SetHeights lets the projection estimate the block height of every row from the genesis time
+func (g Genesis) SetHeights(config *projection.Config) {
+	if g.GenesisTime.IsZero() {
+		return
+	}
+
+	config.GenesisTime = g.GenesisTime.Unix()
+	config.InitialHeight = g.InitialHeight
+}
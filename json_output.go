@@ -0,0 +1,162 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/brianosaurus/challenge2/projection"
+	scenarioModule "github.com/brianosaurus/challenge2/scenario"
+)
+
+// Metadata says what a json report was made from. The format is described by schema/projection.schema.json.
+type Metadata struct {
+	ChainID        string                  `json:"chain_id"`
+	GenesisHash    string                  `json:"genesis_hash"`
+	GenesisTime    *time.Time              `json:"genesis_time,omitempty"`
+	AnchorTime     time.Time               `json:"anchor_time"`
+	Granularity    string                  `json:"granularity"`
+	InflationModel string                  `json:"inflation_model"`
+	MintParams     map[string]interface{}  `json:"mint_params,omitempty"`
+	Scenario       scenarioModule.Scenario `json:"scenario"`
+	CapReachedOn   *int                    `json:"cap_reached_on,omitempty"`
+}
+
+// Record is one row of the projection in a json report. Amounts are strings of whole tokens so
+// they keep their precision. Start marks the first record, the state at the anchor, which has the
+// same day as the end of day 0.
+type Record struct {
+	Start             bool   `json:"start,omitempty"`
+	Day               int    `json:"day"`
+	Date              string `json:"date"`
+	Timestamp         int64  `json:"timestamp"`
+	Height            int64  `json:"height,omitempty"`
+	Period            string `json:"period,omitempty"`
+	TokensUnvesting   string `json:"tokens_unvesting"`
+	Inflation         string `json:"inflation"`
+	StakingRewards    string `json:"staking_rewards"`
+	CirculatingSupply string `json:"circulating_supply"`
+	TotalSupply       string `json:"total_supply"`
+	FeesCollected     string `json:"fees_collected"`
+	TokensBurned      string `json:"tokens_burned"`
+	NetSupplyChange   string `json:"net_supply_change"`
+	Liquid            string `json:"liquid"`
+	StakedVested      string `json:"staked_vested"`
+	StakedUnvested    string `json:"staked_unvested"`
+	Locked            string `json:"locked"`
+	// the tokens unvesting of each cohort when the projection has labels
+	CohortUnvesting map[string]string `json:"cohort_unvesting,omitempty"`
+}
+
+// Report is the document written by WriteJSON
+type Report struct {
+	Metadata Metadata `json:"metadata"`
+	Records  []Record `json:"records"`
+}
+
+// NewMetadata collects the metadata of a projection of genesis made with the scenario
+func NewMetadata(genesis Genesis, scenario scenarioModule.Scenario, projected projection.Projection) Metadata {
+	appState := scenario.AppState(genesis.AppState)
+
+	metadata := Metadata{
+		ChainID:        genesis.ChainID,
+		GenesisHash:    genesis.Hash,
+		AnchorTime:     time.Unix(projected.Anchor, 0).UTC(),
+		Granularity:    projected.Granularity,
+		InflationModel: scenario.Profile(appState),
+		Scenario:       scenario,
+	}
+
+	if !genesis.GenesisTime.IsZero() {
+		genesisTime := genesis.GenesisTime.UTC()
+		metadata.GenesisTime = &genesisTime
+	}
+
+	if mint, ok := appState["mint"].(map[string]interface{}); ok {
+		metadata.MintParams, _ = mint["params"].(map[string]interface{})
+	}
+
+	if projected.CapReachedOn >= 0 {
+		metadata.CapReachedOn = &projected.CapReachedOn
+	}
+
+	return metadata
+}
+
+// NewRecords turns the rows of the projection into json records
+func NewRecords(projected projection.Projection) []Record {
+	records := make([]Record, 0, len(projected.Rows))
+
+	for i, row := range projected.Rows {
+		var cohortUnvesting map[string]string
+		if row.CohortUnvesting != nil {
+			cohortUnvesting = make(map[string]string)
+			for cohort, unvesting := range row.CohortUnvesting {
+				cohortUnvesting[cohort] = unvesting.RoundInt().String()
+			}
+		}
+
+		records = append(records, Record{
+			Start:             i == 0,
+			Day:               row.Day,
+			Date:              projected.Date(row).Format("2006-01-02"),
+			Timestamp:         row.Time,
+			Height:            row.Height,
+			Period:            row.Label(),
+			TokensUnvesting:   row.TokensUnvesting.RoundInt().String(),
+			Inflation:         row.Inflation.String(),
+			StakingRewards:    row.StakingRewards.RoundInt().String(),
+			CirculatingSupply: row.CirculatingSupply.RoundInt().String(),
+			TotalSupply:       row.TotalSupply.RoundInt().String(),
+			FeesCollected:     row.FeesCollected.RoundInt().String(),
+			TokensBurned:      row.TokensBurned.RoundInt().String(),
+			NetSupplyChange:   row.NetSupplyChange.RoundInt().String(),
+			Liquid:            row.Liquid.RoundInt().String(),
+			StakedVested:      row.StakedVested.RoundInt().String(),
+			StakedUnvested:    row.StakedUnvested.RoundInt().String(),
+			Locked:            row.Locked.RoundInt().String(),
+			CohortUnvesting:   cohortUnvesting,
+		})
+	}
+
+	return records
+}
+
+// WriteJSON writes the projection as one json document
+func WriteJSON(writer io.Writer, metadata Metadata, projected projection.Projection) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(Report{Metadata: metadata, Records: NewRecords(projected)})
+}
+
+// WriteNDJSON writes the projection as newline delimited json. The first line holds the metadata
+// and every line after it is a record.
+func WriteNDJSON(writer io.Writer, metadata Metadata, projected projection.Projection) error {
+	encoder := json.NewEncoder(writer)
+
+	err := encoder.Encode(struct {
+		Metadata Metadata `json:"metadata"`
+	}{metadata})
+	if err != nil {
+		return err
+	}
+
+	for _, record := range NewRecords(projected) {
+		if err = encoder.Encode(record); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// the output file for a format when none is given
+func defaultOutput(format string) string {
+	if format == "markdown" {
+		return "genesis_analysis.md"
+	}
+
+	return "genesis_analysis." + format
+}
+
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	mintingTypes "github.com/cosmos/cosmos-sdk/x/mint/types"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"github.com/stretchr/testify/assert"
+
+	mintModule "github.com/brianosaurus/challenge2/mint"
+	"github.com/brianosaurus/challenge2/projection"
+	scenarioModule "github.com/brianosaurus/challenge2/scenario"
+)
+
+func TestWriteJSON(t *testing.T) {
+	var appState = make(map[string]interface{})
+
+	err := json.Unmarshal([]byte(MINTER), &appState)
+	if err != nil {
+		t.Log("Error decoding json")
+		t.FailNow()
+	}
+
+	vestingOnDays := map[int]sdk.Dec{0: sdk.NewDec(1000), 2: sdk.NewDec(2000)}
+	projected := projection.Run(projection.Config{
+		VestingOnDays: &vestingOnDays,
+		TotalSupply:   sdk.NewDec(1000000000),
+		StakedTokens:  sdk.NewDec(500000000),
+		Model:         mintModule.NewStandardModel(mintingTypes.DefaultParams(), mintingTypes.DefaultInitialMinter()),
+		Fees:          projection.NoFees(),
+		Granularity:   projection.GRANULARITY_DAY,
+		CohortVesting: map[string]map[int]sdk.Dec{"team": {2: sdk.NewDec(2000)}, "unlabeled": {0: sdk.NewDec(1000)}},
+		Anchor:        1669100000,
+		GenesisTime:   1669000000,
+		InitialHeight: 1,
+	})
+
+	genesis := Genesis{AppState: appState, ChainID: "umee-1", Hash: strings.Repeat("ab", 32)}
+	metadata := NewMetadata(genesis, scenarioModule.Scenario{Name: "default", Days: 3}, projected)
+	assert.Equal(t, "cosmos", metadata.InflationModel)
+
+	schema, err := jsonschema.Compile("schema/projection.schema.json")
+	if err != nil {
+		t.Log("Error compiling schema", err)
+		t.FailNow()
+	}
+
+	var buf bytes.Buffer
+	assert.Nil(t, WriteJSON(&buf, metadata, projected))
+
+	var document interface{}
+	assert.Nil(t, json.Unmarshal(buf.Bytes(), &document))
+	assert.Nil(t, schema.Validate(document))
+
+	var report Report
+	assert.Nil(t, json.Unmarshal(buf.Bytes(), &report))
+	assert.Equal(t, 4, len(report.Records))
+
+	// the start and the end of day 0 are told apart by start
+	assert.True(t, report.Records[0].Start)
+	assert.False(t, report.Records[1].Start)
+	assert.Equal(t, report.Records[0].Day, report.Records[1].Day)
+	assert.Equal(t, "2022-11-24", report.Records[3].Date)
+	assert.Equal(t, "2000", report.Records[3].TokensUnvesting)
+
+	// the columns of the csv are all there
+	last := projected.Rows[3]
+	assert.Equal(t, last.Liquid.RoundInt().String(), report.Records[3].Liquid)
+	assert.Equal(t, last.StakedVested.RoundInt().String(), report.Records[3].StakedVested)
+	assert.Equal(t, "0", report.Records[3].StakedUnvested)
+	assert.Equal(t, "0", report.Records[3].Locked)
+	assert.Equal(t, map[string]string{"team": "2000", "unlabeled": "0"}, report.Records[3].CohortUnvesting)
+	assert.Equal(t, "umee-1", report.Metadata.ChainID)
+
+	// every line of ndjson is valid on its own
+	metadataSchema, err := jsonschema.Compile("schema/projection.schema.json#/definitions/metadata")
+	assert.Nil(t, err)
+	recordSchema, err := jsonschema.Compile("schema/projection.schema.json#/definitions/record")
+	assert.Nil(t, err)
+
+	buf.Reset()
+	assert.Nil(t, WriteNDJSON(&buf, metadata, projected))
+
+	scanner := bufio.NewScanner(&buf)
+	lines := 0
+
+	for scanner.Scan() {
+		var line map[string]interface{}
+		assert.Nil(t, json.Unmarshal(scanner.Bytes(), &line))
+
+		if lines == 0 {
+			assert.Nil(t, metadataSchema.Validate(line["metadata"]))
+		} else if lines == 1 {
+			assert.Equal(t, true, line["start"])
+			assert.Nil(t, recordSchema.Validate(line))
+		} else {
+			assert.Nil(t, line["start"])
+			assert.Nil(t, recordSchema.Validate(line))
+		}
+
+		lines++
+	}
+
+	assert.Equal(t, 5, lines)
+}
@@ -0,0 +1,138 @@
+package labels
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"sigs.k8s.io/yaml"
+
+	vestingModule "github.com/brianosaurus/challenge2/vesting"
+)
+
+// the cohort of addresses that are not in the label file
+const UNLABELED = "unlabeled"
+
+// Labels maps addresses to the cohort they belong to (team, investors, foundation, airdrop, ...)
+type Labels map[string]string
+
+// Load reads the labels from a csv file with address,label lines (a header line is skipped) or from a
+// YAML or JSON file mapping addresses to labels. The format follows the extension of the file.
+func Load(path string) (Labels, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	labels := Labels{}
+
+	if strings.ToLower(filepath.Ext(path)) != ".csv" {
+		if err = yaml.Unmarshal(raw, &labels); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		return labels, nil
+	}
+
+	reader := csv.NewReader(bytes.NewReader(raw))
+	reader.Comment = '#'
+	reader.TrimLeadingSpace = true
+
+	lines, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	for i, line := range lines {
+		if len(line) != 2 {
+			return nil, fmt.Errorf("%s: line %d: expected address,label", path, i+1)
+		}
+
+		address, label := strings.TrimSpace(line[0]), strings.TrimSpace(line[1])
+		if i == 0 && strings.EqualFold(address, "address") {
+			continue
+		}
+
+		if previous, ok := labels[address]; ok && previous != label {
+			return nil, fmt.Errorf("%s: line %d: %s is labeled both %s and %s", path, i+1, address, previous, label)
+		}
+
+		labels[address] = label
+	}
+
+	return labels, nil
+}
+
+// Label is the cohort of the address, UNLABELED when it is not in the labels
+func (l Labels) Label(address string) string {
+	if label, ok := l[address]; ok && label != "" {
+		return label
+	}
+
+	return UNLABELED
+}
+
+// Schedules splits the vesting schedule counted from anchor by cohort
+func (l Labels) Schedules(accounts []vestingModule.Account, anchor int64) map[string]map[int]sdk.Dec {
+	schedules := make(map[string]map[int]sdk.Dec)
+
+	for _, account := range accounts {
+		label := l.Label(account.Address)
+		if _, ok := schedules[label]; !ok {
+			schedules[label] = make(map[int]sdk.Dec)
+		}
+
+		for day, amount := range account.ScheduleAt(anchor) {
+			if previous, ok := schedules[label][day]; ok {
+				amount = amount.Add(previous)
+			}
+
+			schedules[label][day] = amount
+		}
+	}
+
+	return schedules
+}
+
+// Balances sums the balances in denom of each cohort, amounts of other denoms are left out
+func (l Labels) Balances(balances []vestingModule.Balance, denom string) map[string]sdk.Int {
+	totals := make(map[string]sdk.Int)
+
+	for _, balance := range balances {
+		if balance.Denom != denom {
+			continue
+		}
+
+		label := l.Label(balance.Address)
+		if total, ok := totals[label]; ok {
+			totals[label] = total.Add(balance.Amount)
+		} else {
+			totals[label] = balance.Amount
+		}
+	}
+
+	return totals
+}
+
+// Cohorts returns the keys of a map by cohort in order, UNLABELED last
+func Cohorts[T any](byCohort map[string]T) []string {
+	cohorts := []string{}
+	for cohort := range byCohort {
+		cohorts = append(cohorts, cohort)
+	}
+
+	sort.Slice(cohorts, func(i, j int) bool {
+		if (cohorts[i] == UNLABELED) != (cohorts[j] == UNLABELED) {
+			return cohorts[j] == UNLABELED
+		}
+
+		return cohorts[i] < cohorts[j]
+	})
+
+	return cohorts
+}
@@ -0,0 +1,66 @@
+package labels
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+
+	vestingModule "github.com/brianosaurus/challenge2/vesting"
+)
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	csvPath := filepath.Join(dir, "labels.csv")
+	err := os.WriteFile(csvPath, []byte("address,label\n# the founders\numee1a, team\numee1b,investors\n"), 0644)
+	assert.Nil(t, err)
+
+	labels, err := Load(csvPath)
+	assert.Nil(t, err)
+	assert.Equal(t, Labels{"umee1a": "team", "umee1b": "investors"}, labels)
+	assert.Equal(t, "team", labels.Label("umee1a"))
+	assert.Equal(t, UNLABELED, labels.Label("umee1c"))
+
+	yamlPath := filepath.Join(dir, "labels.yaml")
+	err = os.WriteFile(yamlPath, []byte("umee1a: team\numee1b: investors\n"), 0644)
+	assert.Nil(t, err)
+
+	fromYAML, err := Load(yamlPath)
+	assert.Nil(t, err)
+	assert.Equal(t, labels, fromYAML)
+
+	// an address can only be in one cohort
+	err = os.WriteFile(csvPath, []byte("umee1a,team\numee1a,airdrop\n"), 0644)
+	assert.Nil(t, err)
+	_, err = Load(csvPath)
+	assert.NotNil(t, err)
+}
+
+func TestSchedulesAndBalances(t *testing.T) {
+	const anchor = 1669075200
+
+	labels := Labels{"umee1a": "team", "umee1b": "team"}
+	accounts := []vestingModule.Account{
+		{Address: "umee1a", Type: vestingModule.ACCOUNT_DELAYED, Denom: "uumee", OriginalVesting: sdk.NewInt(100), EndTime: anchor + 86400},
+		{Address: "umee1b", Type: vestingModule.ACCOUNT_DELAYED, Denom: "uumee", OriginalVesting: sdk.NewInt(50), EndTime: anchor + 86400},
+		{Address: "umee1c", Type: vestingModule.ACCOUNT_DELAYED, Denom: "uumee", OriginalVesting: sdk.NewInt(10), EndTime: anchor + 2*86400},
+	}
+
+	schedules := labels.Schedules(accounts, anchor)
+	assert.Equal(t, []string{"team", UNLABELED}, Cohorts(schedules))
+	assert.Equal(t, sdk.NewDec(150), schedules["team"][1])
+	assert.Equal(t, sdk.NewDec(10), schedules[UNLABELED][2])
+
+	// ibc tokens are not added to the staking denom
+	balances := labels.Balances([]vestingModule.Balance{
+		{Address: "umee1a", Denom: "uumee", Amount: sdk.NewInt(7)},
+		{Address: "umee1a", Denom: "ibc/27394FB092D2ECCD56123C74F36E4C1F926001CEADA9CA97EA622B25F41E5EB2", Amount: sdk.NewInt(500)},
+		{Address: "umee1b", Denom: "uumee", Amount: sdk.NewInt(3)},
+		{Address: "umee1z", Denom: "uumee", Amount: sdk.NewInt(1)},
+	}, "uumee")
+	assert.Equal(t, sdk.NewInt(10), balances["team"])
+	assert.Equal(t, sdk.NewInt(1), balances[UNLABELED])
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	lintModule "github.com/brianosaurus/challenge2/lint"
+)
+
+// WriteIssues writes one issue per line and how many there are
+func WriteIssues(writer io.Writer, issues []lintModule.Issue) error {
+	for _, issue := range issues {
+		if _, err := fmt.Fprintln(writer, issue); err != nil {
+			return err
+		}
+	}
+
+	if len(issues) == 0 {
+		_, err := fmt.Fprintln(writer, "No issues found")
+		return err
+	}
+
+	_, err := fmt.Fprintf(writer, "\n%d issues found\n", len(issues))
+	return err
+}
+
+// WriteIssuesJSON writes the issues as one json document
+func WriteIssuesJSON(writer io.Writer, issues []lintModule.Issue) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(issues)
+}
+
+// WriteAddresses writes a table of every address with the hex of its bytes
+func WriteAddresses(writer io.Writer, addresses []lintModule.Address) error {
+	table := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "Source\tAddress\tRaw")
+
+	for _, address := range addresses {
+		raw := address.Raw
+		if address.Error != "" {
+			raw = "error: " + address.Error
+		}
+
+		fmt.Fprintf(table, "%s\t%s\t%s\n", address.Source, address.Address, raw)
+	}
+
+	return table.Flush()
+}
+
+// WriteAddressesJSON writes the addresses as one json document
+func WriteAddressesJSON(writer io.Writer, addresses []lintModule.Address) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(addresses)
+}
+
+// runLint sanity checks a genesis before launch and exits with status 1 when anything is wrong
+func runLint(args []string) {
+	flags := flag.NewFlagSet("lint", flag.ExitOnError)
+	genesisFile := flags.String("genesis", "genesis.json", "the genesis file to check")
+	format := flags.String("format", "text", "the output format: text or json")
+	prefix := flags.String("prefix", "", "the bech32 prefix of account addresses, validators have it followed by valoper (defaults to the one most accounts have)")
+	addresses := flags.Bool("addresses", false, "list every account, balance and validator address with the hex of its bytes instead of the issues")
+	flags.Parse(args)
+
+	if *format != "text" && *format != "json" {
+		fmt.Printf("Unknown format %q\n", *format)
+		return
+	}
+
+	genesis, err := ReadGenesis(*genesisFile)
+	if err != nil {
+		fmt.Println("Error reading genesis file:", err)
+		os.Exit(1)
+	}
+
+	if *addresses {
+		listAddresses(genesis.AppState, *format)
+		return
+	}
+
+	issues := lintModule.Lint(genesis.AppState, *prefix)
+
+	if *format == "json" {
+		err = WriteIssuesJSON(os.Stdout, issues)
+	} else {
+		err = WriteIssues(os.Stdout, issues)
+	}
+
+	if err != nil {
+		fmt.Println("Error writing issues:", err)
+		os.Exit(1)
+	}
+
+	if len(issues) > 0 {
+		os.Exit(1)
+	}
+}
+
+// listAddresses writes the raw addresses of the genesis and exits with status 1 when one can not be decoded
+func listAddresses(appState map[string]interface{}, format string) {
+	addresses := lintModule.RawAddresses(appState)
+
+	var err error
+	if format == "json" {
+		err = WriteAddressesJSON(os.Stdout, addresses)
+	} else {
+		err = WriteAddresses(os.Stdout, addresses)
+	}
+
+	if err != nil {
+		fmt.Println("Error writing addresses:", err)
+		os.Exit(1)
+	}
+
+	for _, address := range addresses {
+		if address.Error != "" {
+			os.Exit(1)
+		}
+	}
+}
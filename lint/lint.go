@@ -0,0 +1,431 @@
+package lint
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+
+	stakingModule "github.com/brianosaurus/challenge2/staking"
+	vestingModule "github.com/brianosaurus/challenge2/vesting"
+)
+
+const (
+	CHECK_PARSE             = "parse"
+	CHECK_VESTING_TIMES     = "vesting-times"
+	CHECK_VESTING_DURATION  = "vesting-duration"
+	CHECK_VESTING_BALANCE   = "vesting-balance"
+	CHECK_DUPLICATE_ADDRESS = "duplicate-address"
+	CHECK_COMMISSION        = "commission"
+	CHECK_INFLATION_BOUNDS  = "inflation-bounds"
+	CHECK_ADDRESS           = "address"
+
+	// validator operator addresses are the account prefix followed by this
+	VALOPER = "valoper"
+
+	// where an address comes from in RawAddresses
+	SOURCE_ACCOUNT   = "account"
+	SOURCE_BALANCE   = "balance"
+	SOURCE_VALIDATOR = "validator"
+	SOURCE_GEN_TX    = "gen_tx"
+)
+
+// Issue is one problem found in a genesis. Address is the account or validator it is about, empty for params.
+// Raw is the hex of the bytes behind a bech32 address, the same on every chain whatever the prefix.
+type Issue struct {
+	Check   string `json:"check"`
+	Address string `json:"address,omitempty"`
+	Raw     string `json:"raw,omitempty"`
+	Message string `json:"message"`
+}
+
+func (i Issue) String() string {
+	str := i.Check
+	if i.Address != "" {
+		str += ": " + i.Address
+	}
+
+	str += ": " + i.Message
+	if i.Raw != "" {
+		str += " (raw " + i.Raw + ")"
+	}
+
+	return str
+}
+
+// Check looks for one kind of problem in an app state
+type Check func(appState map[string]interface{}) []Issue
+
+// CHECKS are run by Lint in order
+var CHECKS = []Check{VestingTimes, VestingBalances, DuplicateAddresses, Commissions, InflationBounds}
+
+// Lint runs every check on the app state and checks the addresses have prefix, or the prefix most accounts
+// have when it is empty. The parsers panic on bad input, a check that panics is reported as a parse issue
+// and the other checks still run.
+func Lint(appState map[string]interface{}, prefix string) []Issue {
+	issues := []Issue{}
+
+	for _, check := range CHECKS {
+		issues = append(issues, run(check, appState)...)
+	}
+
+	return append(issues, run(Addresses(prefix), appState)...)
+}
+
+func run(check Check, appState map[string]interface{}) (issues []Issue) {
+	defer func() {
+		if r := recover(); r != nil {
+			issues = []Issue{{Check: CHECK_PARSE, Message: fmt.Sprint(r)}}
+		}
+	}()
+
+	return check(appState)
+}
+
+// VestingTimes flags continuous vesting accounts that end before they start or vest over no time at all
+func VestingTimes(appState map[string]interface{}) []Issue {
+	issues := []Issue{}
+
+	for _, account := range vestingAccounts(appState) {
+		if account.Type != vestingModule.ACCOUNT_CONTINUOUS {
+			continue
+		}
+
+		switch {
+		case account.EndTime < account.StartTime:
+			issues = append(issues, Issue{Check: CHECK_VESTING_TIMES, Address: account.Address,
+				Message: fmt.Sprintf("end_time %d is before start_time %d", account.EndTime, account.StartTime)})
+		case account.EndTime == account.StartTime:
+			issues = append(issues, Issue{Check: CHECK_VESTING_DURATION, Address: account.Address,
+				Message: fmt.Sprintf("start_time and end_time are both %d, the account vests over zero seconds", account.StartTime)})
+		}
+	}
+
+	return issues
+}
+
+// VestingBalances flags vesting accounts whose original vesting is more than they hold and have delegated
+func VestingBalances(appState map[string]interface{}) []Issue {
+	issues := []Issue{}
+
+	balances := make(map[string]sdk.Int)
+	for _, balance := range vestingModule.GetBalances(appState) {
+		key := balance.Address + " " + balance.Denom
+		if amount, ok := balances[key]; ok {
+			balances[key] = amount.Add(balance.Amount)
+		} else {
+			balances[key] = balance.Amount
+		}
+	}
+
+	for _, account := range vestingAccounts(appState) {
+		held, ok := balances[account.Address+" "+account.Denom]
+		if !ok {
+			held = sdk.ZeroInt()
+		}
+
+		if !account.Delegated.IsNil() {
+			held = held.Add(account.Delegated)
+		}
+
+		if account.OriginalVesting.GT(held) {
+			issues = append(issues, Issue{Check: CHECK_VESTING_BALANCE, Address: account.Address,
+				Message: fmt.Sprintf("original vesting %s%s is more than the %s%s balance and delegations", account.OriginalVesting,
+					account.Denom, held, account.Denom)})
+		}
+	}
+
+	return issues
+}
+
+// DuplicateAddresses flags addresses that have more than one auth account, bank balance or gen_tx validator
+func DuplicateAddresses(appState map[string]interface{}) []Issue {
+	issues := []Issue{}
+
+	accounts := []string{}
+	for _, account := range vestingModule.GetAuthAccounts(appState) {
+		accounts = append(accounts, account.Address)
+	}
+
+	balances := []string{}
+	bank := (appState["bank"]).(map[string]interface{})
+	for _, balance := range (bank["balances"]).([]interface{}) {
+		address, _ := balance.(map[string]interface{})["address"].(string)
+		balances = append(balances, address)
+	}
+
+	validators := []string{}
+	for _, validator := range stakingModule.GetValidators(appState) {
+		validators = append(validators, validator.OperatorAddress)
+	}
+
+	for _, section := range []struct {
+		name      string
+		addresses []string
+	}{
+		{"auth accounts", accounts},
+		{"bank balances", balances},
+		{"validators", validators},
+	} {
+		for _, address := range duplicates(section.addresses) {
+			issues = append(issues, Issue{Check: CHECK_DUPLICATE_ADDRESS, Address: address,
+				Message: "is in " + section.name + " more than once"})
+		}
+	}
+
+	return issues
+}
+
+// Commissions flags gen_txs whose commission the staking module would reject
+func Commissions(appState map[string]interface{}) []Issue {
+	issues := []Issue{}
+
+	for _, validator := range stakingModule.GetValidators(appState) {
+		// only validators from gen_txs have a delegator address
+		if validator.DelegatorAddress == "" {
+			continue
+		}
+
+		for _, problem := range commissionProblems(validator) {
+			issues = append(issues, Issue{Check: CHECK_COMMISSION, Address: validator.OperatorAddress, Message: problem})
+		}
+	}
+
+	return issues
+}
+
+// commissionProblems are the bounds of Commission.Validate in the staking module
+func commissionProblems(validator stakingModule.Validator) []string {
+	problems := []string{}
+
+	switch {
+	case validator.MaxRate.IsNegative():
+		problems = append(problems, fmt.Sprintf("max_rate %s is negative", validator.MaxRate))
+	case validator.MaxRate.GT(sdk.OneDec()):
+		problems = append(problems, fmt.Sprintf("max_rate %s is more than 1", validator.MaxRate))
+	}
+
+	switch {
+	case validator.CommissionRate.IsNegative():
+		problems = append(problems, fmt.Sprintf("rate %s is negative", validator.CommissionRate))
+	case validator.CommissionRate.GT(validator.MaxRate):
+		problems = append(problems, fmt.Sprintf("rate %s is more than max_rate %s", validator.CommissionRate, validator.MaxRate))
+	}
+
+	switch {
+	case validator.MaxChangeRate.IsNegative():
+		problems = append(problems, fmt.Sprintf("max_change_rate %s is negative", validator.MaxChangeRate))
+	case validator.MaxChangeRate.GT(validator.MaxRate):
+		problems = append(problems, fmt.Sprintf("max_change_rate %s is more than max_rate %s", validator.MaxChangeRate, validator.MaxRate))
+	}
+
+	return problems
+}
+
+// InflationBounds flags mint params where inflation_min is more than inflation_max
+func InflationBounds(appState map[string]interface{}) []Issue {
+	mint, _ := appState["mint"].(map[string]interface{})
+	params, _ := mint["params"].(map[string]interface{})
+
+	minimum, hasMin := params["inflation_min"].(string)
+	maximum, hasMax := params["inflation_max"].(string)
+	if !hasMin || !hasMax {
+		return nil
+	}
+
+	if sdk.MustNewDecFromStr(minimum).GT(sdk.MustNewDecFromStr(maximum)) {
+		return []Issue{{Check: CHECK_INFLATION_BOUNDS, Message: fmt.Sprintf("inflation_min %s is more than inflation_max %s", minimum, maximum)}}
+	}
+
+	return nil
+}
+
+// Addresses decodes every account, balance and validator address as bech32 and flags the ones with a bad
+// checksum or the wrong prefix: prefix for accounts and prefix followed by valoper for validators. A gen_tx
+// has to come from the account of the validator it creates.
+func Addresses(prefix string) Check {
+	return func(appState map[string]interface{}) []Issue {
+		accounts := []string{}
+		for _, account := range vestingModule.GetAuthAccounts(appState) {
+			accounts = append(accounts, account.Address)
+		}
+
+		bank := (appState["bank"]).(map[string]interface{})
+		for _, balance := range (bank["balances"]).([]interface{}) {
+			address, _ := balance.(map[string]interface{})["address"].(string)
+			accounts = append(accounts, address)
+		}
+
+		expected := prefix
+		if expected == "" {
+			expected = commonPrefix(accounts)
+		}
+
+		issues := []Issue{}
+		checked := make(map[string]bool)
+
+		check := func(address string, hrp string) {
+			if checked[address] {
+				return
+			}
+			checked[address] = true
+
+			if issue, ok := checkAddress(address, hrp); !ok {
+				issues = append(issues, issue)
+			}
+		}
+
+		for _, address := range accounts {
+			check(address, expected)
+		}
+
+		valoper := ""
+		if expected != "" {
+			valoper = expected + VALOPER
+		}
+
+		for _, validator := range stakingModule.GetValidators(appState) {
+			check(validator.OperatorAddress, valoper)
+
+			if validator.DelegatorAddress == "" {
+				continue
+			}
+
+			check(validator.DelegatorAddress, expected)
+
+			operator, operatorErr := RawAddress(validator.OperatorAddress)
+			delegator, delegatorErr := RawAddress(validator.DelegatorAddress)
+			if operatorErr == nil && delegatorErr == nil && operator != delegator {
+				issues = append(issues, Issue{Check: CHECK_ADDRESS, Address: validator.OperatorAddress, Raw: operator,
+					Message: fmt.Sprintf("gen_tx comes from %s (raw %s), not the account of the validator", validator.DelegatorAddress, delegator)})
+			}
+		}
+
+		return issues
+	}
+}
+
+// Address is a bech32 address of the genesis with the hex of its bytes, Error says why it could not be decoded
+type Address struct {
+	Source  string `json:"source"`
+	Address string `json:"address"`
+	Raw     string `json:"raw,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// RawAddresses decodes every auth account, bank balance, validator and gen_tx delegator address in the order
+// of genesis. An address is listed once for each source it is in.
+func RawAddresses(appState map[string]interface{}) []Address {
+	addresses := []Address{}
+	listed := make(map[string]bool)
+
+	add := func(source string, address string) {
+		if listed[source+" "+address] {
+			return
+		}
+		listed[source+" "+address] = true
+
+		raw, err := RawAddress(address)
+		if err != nil {
+			addresses = append(addresses, Address{Source: source, Address: address, Error: err.Error()})
+			return
+		}
+
+		addresses = append(addresses, Address{Source: source, Address: address, Raw: raw})
+	}
+
+	for _, account := range vestingModule.GetAuthAccounts(appState) {
+		add(SOURCE_ACCOUNT, account.Address)
+	}
+
+	bank := (appState["bank"]).(map[string]interface{})
+	for _, balance := range (bank["balances"]).([]interface{}) {
+		address, _ := balance.(map[string]interface{})["address"].(string)
+		add(SOURCE_BALANCE, address)
+	}
+
+	for _, validator := range stakingModule.GetValidators(appState) {
+		add(SOURCE_VALIDATOR, validator.OperatorAddress)
+
+		if validator.DelegatorAddress != "" {
+			add(SOURCE_GEN_TX, validator.DelegatorAddress)
+		}
+	}
+
+	return addresses
+}
+
+// RawAddress is the hex of the bytes of a bech32 address
+func RawAddress(address string) (string, error) {
+	_, raw, err := bech32.DecodeAndConvert(address)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.ToUpper(hex.EncodeToString(raw)), nil
+}
+
+// checkAddress decodes address and compares its prefix with expected, an empty expected prefix matches every one
+func checkAddress(address string, expected string) (Issue, bool) {
+	hrp, raw, err := bech32.DecodeAndConvert(address)
+	if err != nil {
+		return Issue{Check: CHECK_ADDRESS, Address: address, Message: err.Error()}, false
+	}
+
+	if len(raw) == 0 {
+		return Issue{Check: CHECK_ADDRESS, Address: address, Message: "has no bytes"}, false
+	}
+
+	if expected != "" && hrp != expected {
+		return Issue{Check: CHECK_ADDRESS, Address: address, Raw: strings.ToUpper(hex.EncodeToString(raw)),
+			Message: fmt.Sprintf("has prefix %s, expected %s", hrp, expected)}, false
+	}
+
+	return Issue{}, true
+}
+
+// commonPrefix is the prefix most of the addresses have, ties go to the first in order
+func commonPrefix(addresses []string) string {
+	counts := make(map[string]int)
+	for _, address := range addresses {
+		if hrp, _, err := bech32.DecodeAndConvert(address); err == nil {
+			counts[hrp]++
+		}
+	}
+
+	common := ""
+	for hrp, count := range counts {
+		if count > counts[common] || (count == counts[common] && hrp < common) {
+			common = hrp
+		}
+	}
+
+	return common
+}
+
+func vestingAccounts(appState map[string]interface{}) []vestingModule.Account {
+	continuousAccounts, delayedAccounts := vestingModule.GetVestingAccounts(appState)
+
+	return vestingModule.GetAccounts(continuousAccounts, delayedAccounts)
+}
+
+// duplicates returns the addresses that are in the list more than once, in order
+func duplicates(addresses []string) []string {
+	counts := make(map[string]int)
+	for _, address := range addresses {
+		counts[address]++
+	}
+
+	repeated := []string{}
+	for address, count := range counts {
+		if count > 1 {
+			repeated = append(repeated, address)
+		}
+	}
+	sort.Strings(repeated)
+
+	return repeated
+}
@@ -0,0 +1,321 @@
+package lint
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	APP_STATE =
+`{
+	"auth": {
+		"accounts": [
+			{
+				"@type": "/cosmos.vesting.v1beta1.ContinuousVestingAccount",
+				"base_vesting_account": {
+					"base_account": {
+						"address": "umee1wqr08242ysrepqgzm6q0mn7ndcnjlsf6vdxd0v",
+						"pub_key": null,
+						"account_number": "0",
+						"sequence": "0"
+					},
+					"original_vesting": [
+						{
+							"denom": "uumee",
+							"amount": "1000"
+						}
+					],
+					"delegated_free": [],
+					"delegated_vesting": [],
+					"end_time": "1660582800"
+				},
+				"start_time": "1723741200"
+			},
+			{
+				"@type": "/cosmos.vesting.v1beta1.ContinuousVestingAccount",
+				"base_vesting_account": {
+					"base_account": {
+						"address": "umee1qqq8fdsrcsz4jnlvrcqa6ds2ruflgrgeguyeh0",
+						"pub_key": null,
+						"account_number": "1",
+						"sequence": "0"
+					},
+					"original_vesting": [
+						{
+							"denom": "uumee",
+							"amount": "1000"
+						}
+					],
+					"delegated_free": [],
+					"delegated_vesting": [],
+					"end_time": "1660582800"
+				},
+				"start_time": "1660582800"
+			},
+			{
+				"@type": "/cosmos.vesting.v1beta1.DelayedVestingAccount",
+				"base_vesting_account": {
+					"base_account": {
+						"address": "umee1agzky2ak6xs5vve3c2wzjtqdq7fwadcgj2mxf9",
+						"pub_key": null,
+						"account_number": "2",
+						"sequence": "0"
+					},
+					"original_vesting": [
+						{
+							"denom": "uumee",
+							"amount": "309282000000"
+						}
+					],
+					"delegated_free": [],
+					"delegated_vesting": [
+						{
+							"denom": "uumee",
+							"amount": "1000"
+						}
+					],
+					"end_time": "1676480400"
+				}
+			},
+			{
+				"@type": "/cosmos.auth.v1beta1.BaseAccount",
+				"address": "umee1n3mhyp9fvcmuu8l0q8qvjy07x0rql8q4dtsqwh",
+				"pub_key": null,
+				"account_number": "3",
+				"sequence": "0"
+			},
+			{
+				"@type": "/cosmos.auth.v1beta1.BaseAccount",
+				"address": "umee1n3mhyp9fvcmuu8l0q8qvjy07x0rql8q4dtsqwh",
+				"pub_key": null,
+				"account_number": "4",
+				"sequence": "0"
+			}
+		]
+	},
+	"bank": {
+		"balances": [
+			{
+				"address": "umee1wqr08242ysrepqgzm6q0mn7ndcnjlsf6vdxd0v",
+				"coins": [
+					{
+						"denom": "uumee",
+						"amount": "1000"
+					}
+				]
+			},
+			{
+				"address": "umee1qqq8fdsrcsz4jnlvrcqa6ds2ruflgrgeguyeh0",
+				"coins": [
+					{
+						"denom": "uumee",
+						"amount": "600"
+					}
+				]
+			},
+			{
+				"address": "umee1qqq8fdsrcsz4jnlvrcqa6ds2ruflgrgeguyeh0",
+				"coins": [
+					{
+						"denom": "uumee",
+						"amount": "400"
+					}
+				]
+			},
+			{
+				"address": "umee1agzky2ak6xs5vve3c2wzjtqdq7fwadcgj2mxf9",
+				"coins": [
+					{
+						"denom": "uumee",
+						"amount": "309281998000"
+					}
+				]
+			}
+		]
+	},
+	"genutil": {
+		"gen_txs": [
+			{
+				"body": {
+					"messages": [
+						{
+							"@type": "/cosmos.staking.v1beta1.MsgCreateValidator",
+							"description": {
+								"moniker": "0base.vc"
+							},
+							"commission": {
+								"rate": "0.200000000000000000",
+								"max_rate": "0.100000000000000000",
+								"max_change_rate": "0.010000000000000000"
+							},
+							"min_self_delegation": "1",
+							"delegator_address": "umee1n3mhyp9fvcmuu8l0q8qvjy07x0rql8q4dtsqwh",
+							"validator_address": "umeevaloper1n3mhyp9fvcmuu8l0q8qvjy07x0rql8q4d0h0la",
+							"value": {
+								"denom": "uumee",
+								"amount": "1000000"
+							}
+						}
+					]
+				}
+			}
+		]
+	},
+	"mint": {
+		"params": {
+			"mint_denom": "uumee",
+			"inflation_max": "0.070000000000000000",
+			"inflation_min": "0.140000000000000000"
+		}
+	}
+}`
+)
+
+func decode(t *testing.T, raw string) map[string]interface{} {
+	var appState = make(map[string]interface{})
+
+	err := json.Unmarshal([]byte(raw), &appState)
+	if err != nil {
+		t.Log("Error decoding json")
+		t.FailNow()
+	}
+
+	return appState
+}
+
+func TestLint(t *testing.T) {
+	issues := Lint(decode(t, APP_STATE), "")
+
+	checks := []string{}
+	for _, issue := range issues {
+		checks = append(checks, issue.Check+" "+issue.Address)
+	}
+
+	assert.Equal(t, []string{
+		CHECK_VESTING_DURATION + " umee1qqq8fdsrcsz4jnlvrcqa6ds2ruflgrgeguyeh0",
+		CHECK_VESTING_TIMES + " umee1wqr08242ysrepqgzm6q0mn7ndcnjlsf6vdxd0v",
+		CHECK_VESTING_BALANCE + " umee1agzky2ak6xs5vve3c2wzjtqdq7fwadcgj2mxf9",
+		CHECK_DUPLICATE_ADDRESS + " umee1n3mhyp9fvcmuu8l0q8qvjy07x0rql8q4dtsqwh",
+		CHECK_DUPLICATE_ADDRESS + " umee1qqq8fdsrcsz4jnlvrcqa6ds2ruflgrgeguyeh0",
+		CHECK_COMMISSION + " umeevaloper1n3mhyp9fvcmuu8l0q8qvjy07x0rql8q4d0h0la",
+		CHECK_INFLATION_BOUNDS + " ",
+	}, checks)
+
+	// the balance and delegation together are 1000 short
+	assert.Equal(t, "original vesting 309282000000uumee is more than the 309281999000uumee balance and delegations", issues[2].Message)
+	assert.Equal(t, "is in bank balances more than once", issues[4].Message)
+	assert.Equal(t, "rate 0.200000000000000000 is more than max_rate 0.100000000000000000", issues[5].Message)
+	assert.Equal(t, "inflation-bounds: inflation_min 0.140000000000000000 is more than inflation_max 0.070000000000000000", issues[6].String())
+}
+
+func TestLintParse(t *testing.T) {
+	appState := decode(t, APP_STATE)
+
+	// a bad amount makes the checks that read balances fail, the rest still run
+	balances := appState["bank"].(map[string]interface{})["balances"].([]interface{})
+	coins := balances[0].(map[string]interface{})["coins"].([]interface{})
+	coins[0].(map[string]interface{})["amount"] = "lots"
+
+	issues := Lint(appState, "")
+
+	assert.Equal(t, CHECK_VESTING_DURATION, issues[0].Check)
+	assert.Equal(t, CHECK_PARSE, issues[2].Check)
+	assert.Contains(t, issues[2].Message, "Error parsing amount of umee1wqr08242ysrepqgzm6q0mn7ndcnjlsf6vdxd0v")
+	assert.Equal(t, CHECK_INFLATION_BOUNDS, issues[len(issues)-1].Check)
+}
+
+func TestCommissionProblems(t *testing.T) {
+	appState := decode(t, APP_STATE)
+
+	genTxs := appState["genutil"].(map[string]interface{})["gen_txs"].([]interface{})
+	body := genTxs[0].(map[string]interface{})["body"].(map[string]interface{})
+	commission := body["messages"].([]interface{})[0].(map[string]interface{})["commission"].(map[string]interface{})
+
+	commission["rate"] = "0.050000000000000000"
+	assert.Empty(t, Commissions(appState))
+
+	commission["max_rate"] = "1.500000000000000000"
+	commission["max_change_rate"] = "-0.010000000000000000"
+
+	issues := Commissions(appState)
+	assert.Len(t, issues, 2)
+	assert.Equal(t, "max_rate 1.500000000000000000 is more than 1", issues[0].Message)
+	assert.Equal(t, "max_change_rate -0.010000000000000000 is negative", issues[1].Message)
+}
+
+func TestAddresses(t *testing.T) {
+	appState := decode(t, APP_STATE)
+
+	raw, err := RawAddress("umee1agzky2ak6xs5vve3c2wzjtqdq7fwadcgj2mxf9")
+	assert.Nil(t, err)
+	assert.Len(t, raw, 40)
+
+	// the same account on another chain has the same raw address
+	rawBytes, _ := hex.DecodeString(raw)
+	cosmos, err := bech32.ConvertAndEncode("cosmos", rawBytes)
+	assert.Nil(t, err)
+
+	cosmosRaw, err := RawAddress(cosmos)
+	assert.Nil(t, err)
+	assert.Equal(t, raw, cosmosRaw)
+
+	// every address of the fixture is fine
+	assert.Empty(t, Addresses("")(appState))
+	assert.Empty(t, Addresses("umee")(appState))
+
+	balances := appState["bank"].(map[string]interface{})["balances"].([]interface{})
+	balances[0].(map[string]interface{})["address"] = "umee1wqr08242ysrepqgzm6q0mn7ndcnjlsf6vdxd0w"
+	balances[3].(map[string]interface{})["address"] = cosmos
+
+	genTxs := appState["genutil"].(map[string]interface{})["gen_txs"].([]interface{})
+	body := genTxs[0].(map[string]interface{})["body"].(map[string]interface{})
+	body["messages"].([]interface{})[0].(map[string]interface{})["delegator_address"] = "umee1agzky2ak6xs5vve3c2wzjtqdq7fwadcgj2mxf9"
+
+	issues := Addresses("")(appState)
+	assert.Len(t, issues, 3)
+
+	// a typo breaks the checksum
+	assert.Equal(t, "umee1wqr08242ysrepqgzm6q0mn7ndcnjlsf6vdxd0w", issues[0].Address)
+	assert.Contains(t, issues[0].Message, "invalid checksum")
+	assert.Equal(t, "", issues[0].Raw)
+
+	// umee is still the prefix of most accounts
+	assert.Equal(t, Issue{Check: CHECK_ADDRESS, Address: cosmos, Raw: raw, Message: "has prefix cosmos, expected umee"}, issues[1])
+
+	assert.Equal(t, "umeevaloper1n3mhyp9fvcmuu8l0q8qvjy07x0rql8q4d0h0la", issues[2].Address)
+	assert.Equal(t, "gen_tx comes from umee1agzky2ak6xs5vve3c2wzjtqdq7fwadcgj2mxf9 (raw "+raw+"), not the account of the validator",
+		issues[2].Message)
+
+	// with another prefix every account and the validator are flagged
+	issues = Addresses("cosmos")(appState)
+	assert.Equal(t, "has prefix umeevaloper, expected cosmosvaloper", issues[len(issues)-2].Message)
+	assert.Contains(t, issues[1].String(), "has prefix umee, expected cosmos (raw ")
+}
+
+func TestRawAddresses(t *testing.T) {
+	appState := decode(t, APP_STATE)
+
+	balances := appState["bank"].(map[string]interface{})["balances"].([]interface{})
+	balances[0].(map[string]interface{})["address"] = "umee1wqr08242ysrepqgzm6q0mn7ndcnjlsf6vdxd0w"
+
+	addresses := RawAddresses(appState)
+
+	// 4 accounts, 3 balances, the validator and the account its gen_tx comes from
+	assert.Len(t, addresses, 9)
+
+	raw, _ := RawAddress("umee1n3mhyp9fvcmuu8l0q8qvjy07x0rql8q4dtsqwh")
+	assert.Equal(t, Address{Source: SOURCE_ACCOUNT, Address: "umee1n3mhyp9fvcmuu8l0q8qvjy07x0rql8q4dtsqwh", Raw: raw}, addresses[3])
+
+	assert.Equal(t, SOURCE_BALANCE, addresses[4].Source)
+	assert.Equal(t, "", addresses[4].Raw)
+	assert.Contains(t, addresses[4].Error, "invalid checksum")
+
+	// a validator has the same bytes as the account that created it
+	assert.Equal(t, SOURCE_VALIDATOR, addresses[7].Source)
+	assert.Equal(t, raw, addresses[7].Raw)
+	assert.Equal(t, Address{Source: SOURCE_GEN_TX, Address: "umee1n3mhyp9fvcmuu8l0q8qvjy07x0rql8q4dtsqwh", Raw: raw}, addresses[8])
+}
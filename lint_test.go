@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	lintModule "github.com/brianosaurus/challenge2/lint"
+)
+
+func TestWriteIssues(t *testing.T) {
+	var buffer bytes.Buffer
+
+	assert.Nil(t, WriteIssues(&buffer, []lintModule.Issue{}))
+	assert.Equal(t, "No issues found\n", buffer.String())
+
+	// the vesting accounts of the test fixtures have no balances
+	issues := lintModule.Lint(newTestAppState(t), "umee")
+	assert.Len(t, issues, 2)
+	for _, issue := range issues {
+		assert.Equal(t, lintModule.CHECK_VESTING_BALANCE, issue.Check)
+	}
+
+	issues = []lintModule.Issue{
+		{Check: lintModule.CHECK_DUPLICATE_ADDRESS, Address: "umee1d", Message: "is in auth accounts more than once"},
+		{Check: lintModule.CHECK_INFLATION_BOUNDS, Message: "inflation_min 0.2 is more than inflation_max 0.1"},
+	}
+
+	buffer.Reset()
+	assert.Nil(t, WriteIssues(&buffer, issues))
+	assert.Equal(t, "duplicate-address: umee1d: is in auth accounts more than once\n"+
+		"inflation-bounds: inflation_min 0.2 is more than inflation_max 0.1\n\n2 issues found\n", buffer.String())
+
+	buffer.Reset()
+	assert.Nil(t, WriteIssuesJSON(&buffer, issues))
+
+	var decoded []lintModule.Issue
+	assert.Nil(t, json.Unmarshal(buffer.Bytes(), &decoded))
+	assert.Equal(t, issues, decoded)
+}
+
+func TestWriteAddresses(t *testing.T) {
+	addresses := []lintModule.Address{
+		{Source: lintModule.SOURCE_ACCOUNT, Address: "umee1n3mhyp9fvcmuu8l0q8qvjy07x0rql8q4dtsqwh", Raw: "9C777204A9663793C2FF01C0C913FE33C60F9C15"},
+		{Source: lintModule.SOURCE_BALANCE, Address: "umee1d", Error: "decoding bech32 failed"},
+	}
+
+	var buffer bytes.Buffer
+	assert.Nil(t, WriteAddresses(&buffer, addresses))
+	assert.Equal(t, "Source   Address                                      Raw\n"+
+		"account  umee1n3mhyp9fvcmuu8l0q8qvjy07x0rql8q4dtsqwh  9C777204A9663793C2FF01C0C913FE33C60F9C15\n"+
+		"balance  umee1d                                       error: decoding bech32 failed\n", buffer.String())
+
+	buffer.Reset()
+	assert.Nil(t, WriteAddressesJSON(&buffer, addresses))
+
+	var decoded []lintModule.Address
+	assert.Nil(t, json.Unmarshal(buffer.Bytes(), &decoded))
+	assert.Equal(t, addresses, decoded)
+}
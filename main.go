@@ -2,90 +2,99 @@ package main
 
 import (
 	"encoding/csv"
-	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
-	"sort"
 	"strconv"
+	"time"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 
-	mintingTypes "github.com/cosmos/cosmos-sdk/x/mint/types"
+	labelsModule "github.com/brianosaurus/challenge2/labels"
+	"github.com/brianosaurus/challenge2/projection"
+	scenarioModule "github.com/brianosaurus/challenge2/scenario"
 	vestingModule "github.com/brianosaurus/challenge2/vesting"
-	stakingModule "github.com/brianosaurus/challenge2/staking"
-	mintModule "github.com/brianosaurus/challenge2/mint"
 )
 
-const (
-	SECONDS_PER_BLOCK = 5
-)
 
+// CSVOptions are the optional parts of the csv. When Genesis is set a header block saying what the
+// report was made from is written above the rows as # comments. Cohorts adds a column with the unlocks
+// of each cohort and CohortBalances a header line with the balance of each cohort.
+type CSVOptions struct {
+	Genesis        *Genesis
+	Timestamps     bool
+	Heights        bool
+	Cohorts        []string
+	CohortBalances map[string]sdk.Int
+}
 
-func WriteCSV(writer *csv.Writer, vestingOnDays *map[int]sdk.Dec, totalSupply sdk.Dec, stakedTokens sdk.Dec, 
-	minter mintingTypes.Minter, params mintingTypes.Params) {
-	// write the header
-	err := writer.Write([]string{"Days Since Genesis Analyzed", "Tokens Unvesting", "Inflation", "Staking Rewards", "Circulating Supply", "Total Supply"})
-	if err != nil {
-		fmt.Println("Error writing to csv")
-		return
-	}
+// WriteCSV writes the projection to the csv, one row per period. Unless the rows are days the
+// period of each row is written next to the day.
+func WriteCSV(writer *csv.Writer, projected projection.Projection, options CSVOptions) {
+	if options.Genesis != nil {
+		writer.Write([]string{"# Anchor Time", time.Unix(projected.Anchor, 0).UTC().Format(time.RFC3339)})
+		writer.Write([]string{"# Chain ID", options.Genesis.ChainID})
+		writer.Write([]string{"# Genesis Hash", options.Genesis.Hash})
 
-	days := make([]int, 0, len(*vestingOnDays))
+		for _, cohort := range labelsModule.Cohorts(options.CohortBalances) {
+			writer.Write([]string{"# Balance (" + cohort + ")", options.CohortBalances[cohort].String()})
+		}
+	}
 
-	for day := range *vestingOnDays {
-		days = append(days, day)
+	header := []string{"Days Since Genesis Analyzed", "Date"}
+	if options.Timestamps {
+		header = append(header, "Timestamp")
 	}
 
-	sort.Ints(days)
+	if options.Heights {
+		header = append(header, "Block Height")
+	}
 
-	// is total in circulation really correct here? Vesting tokens are unaccessible however they came from an
-	// account initially. So they have been minted already. In any case, I'll go with the assumption that
-	// tokens that haven't yet been vested are not in circulation however staked tokens are in circulation
-	// because staked tokens can be retrived even if there is a lockout period. Excluding staked yet to vest tokens.
-	totalInCirculation := totalSupply
+	if hasPeriods(projected) {
+		header = append(header, "Period")
+	}
 
-	for day := range days {
-		if _, ok := (*vestingOnDays)[day]; !ok {
-			continue
-		}
+	header = append(header, "Tokens Unvesting", "Inflation", "Staking Rewards", "Circulating Supply", "Total Supply",
+		"Fees Collected", "Tokens Burned", "Net Supply Change", "Liquid", "Staked Vested", "Staked Unvested", "Locked")
 
-		totalInCirculation = totalInCirculation.Sub((*vestingOnDays)[day])
+	for _, cohort := range options.Cohorts {
+		header = append(header, "Tokens Unvesting ("+cohort+")")
 	}
 
-	stakingRewards := sdk.NewDec(0)
-
-	csvStr := []string{"0", sdk.NewDec(0).RoundInt().String(), minter.Inflation.String(), stakingRewards.RoundInt().String(), 
-		totalInCirculation.RoundInt().String(), totalSupply.RoundInt().String()}
-	writer.Write(csvStr)
+	// write the header
+	err := writer.Write(header)
+	if err != nil {
+		fmt.Println("Error writing to csv")
+		return
+	}
 
-	for day := range days {
-		if _, ok := (*vestingOnDays)[day]; !ok {
-			continue
+	for i, day := range projected.Rows {
+		csvStr := []string{projected.DayColumn(i), projected.Date(day).Format("2006-01-02")}
+		if options.Timestamps {
+			csvStr = append(csvStr, strconv.FormatInt(day.Time, 10))
 		}
 
-		// calculate inflation for the previous day (rewards are calculated and rewarded every block)
-		stakingRatio := stakedTokens.Quo(totalSupply)
+		if options.Heights {
+			csvStr = append(csvStr, strconv.FormatInt(day.Height, 10))
+		}
 
+		if hasPeriods(projected) {
+			csvStr = append(csvStr, day.Label())
+		}
 
-		// calculate rewards for each hour of the previous day
-		for i := 1; i < 24; i++ {
-			// inflation changes hourly so make these calculations hourly
-			minter.Inflation = minter.NextInflationRate(params, stakingRatio)
-			minter.AnnualProvisions = minter.NextAnnualProvisions(params, sdk.NewInt(totalSupply.RoundInt64()))
-			coins := minter.BlockProvision(params)
+		csvStr = append(csvStr, day.TokensUnvesting.RoundInt().String(), day.Inflation.String(), day.StakingRewards.RoundInt().String(), 
+			day.CirculatingSupply.RoundInt().String(), day.TotalSupply.RoundInt().String(), day.FeesCollected.RoundInt().String(),
+			day.TokensBurned.RoundInt().String(), day.NetSupplyChange.RoundInt().String(), day.Liquid.RoundInt().String(),
+			day.StakedVested.RoundInt().String(), day.StakedUnvested.RoundInt().String(), day.Locked.RoundInt().String())
 
-			// rewards are distributed every block
-			for j := 0; j < (60 / SECONDS_PER_BLOCK); j++ {
-				stakingRewards = stakingRewards.Add(sdk.NewDecFromBigInt(coins.Amount.BigInt()))
-				totalInCirculation = totalInCirculation.Add(sdk.NewDecFromBigInt(coins.Amount.BigInt()))
-				totalSupply = totalSupply.Add(sdk.NewDecFromBigInt(coins.Amount.BigInt()))
+		for _, cohort := range options.Cohorts {
+			unvesting, ok := day.CohortUnvesting[cohort]
+			if !ok {
+				unvesting = sdk.NewDec(0)
 			}
-		}
 
-		totalInCirculation = totalInCirculation.Add((*vestingOnDays)[day]) // add recently unvested tokens to total in circulation
-		csvStr = []string{strconv.Itoa(day), (*vestingOnDays)[day].RoundInt().String(), minter.Inflation.String(), stakingRewards.RoundInt().String(), 
-			totalInCirculation.RoundInt().String(), totalSupply.RoundInt().String()}
+			csvStr = append(csvStr, unvesting.RoundInt().String())
+		}
 
 		writer.Write(csvStr)
 	}
@@ -93,56 +102,166 @@ func WriteCSV(writer *csv.Writer, vestingOnDays *map[int]sdk.Dec, totalSupply sd
 	writer.Flush()
 }
 
+func hasPeriods(projected projection.Projection) bool {
+	return projected.Granularity != "" && projected.Granularity != projection.GRANULARITY_DAY
+}
+
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "scenarios":
+			runScenarios(os.Args[2:])
+			return
+		case "montecarlo":
+			runMonteCarlo(os.Args[2:])
+			return
+		case "report":
+			runReport(os.Args[2:])
+			return
+		case "chart":
+			runCharts(os.Args[2:])
+			return
+		case "export":
+			runExport(os.Args[2:])
+			return
+		case "calendar":
+			runCalendar(os.Args[2:])
+			return
+		case "accounts":
+			runAccounts(os.Args[2:])
+			return
+		case "at":
+			runAt(os.Args[2:])
+			return
+		case "backtest":
+			runBacktest(os.Args[2:])
+			return
+		case "diff":
+			runDiff(os.Args[2:])
+			return
+		case "compare":
+			runCompare(os.Args[2:])
+			return
+		case "lint":
+			runLint(os.Args[2:])
+			return
+		}
+	}
+
 	// get the data from the json file
 	// flag for the output csv file
 	var csvStr string
 	var genesisFile string
+	var settings scenarioModule.Scenario
+	var timestamps bool
+	var heights bool
+	var format string
+	var out string
+	var labelsFile string
 	flag.StringVar(&csvStr, "csv", "genesis_analysis.csv", "the csv file to output the data to")
+	flag.StringVar(&format, "format", "csv", "the output format: csv, json, ndjson or markdown")
+	flag.StringVar(&out, "out", "", "the file to output the data to (defaults to -csv for csv and genesis_analysis.<format> otherwise)")
 	flag.StringVar(&genesisFile, "genesis", "genesis.json", "the genesis file to analyze")
+	flag.StringVar(&settings.InflationModel, "inflation-model", "", "the inflation model to use: cosmos, osmosis, evmos or juno (detected from genesis if empty)")
+	flag.StringVar(&settings.MaxSupply, "max-supply", "", "stop minting once the total supply reaches this amount")
+	flag.StringVar(&settings.TaperSupply, "taper-supply", "0", "slow minting down linearly over this many tokens before the max supply")
+	flag.StringVar(&settings.Fees.DailyVolume, "fee-volume", "0", "the amount of tokens paid in fees every day")
+	flag.StringVar(&settings.Fees.BurnRatio, "burn-ratio", "0", "the share of fees that is burned")
+	flag.StringVar(&settings.Fees.StakerShare, "staker-share", "0", "the share of fees that is paid to stakers")
+	flag.IntVar(&settings.Days, "days", 0, "the number of days to project (defaults to the day of the last unlock)")
+	flag.StringVar(&settings.Until, "until", "", "the date to project until, RFC3339 or YYYY-MM-DD (instead of -days)")
+	flag.StringVar(&settings.Granularity, "granularity", "day", "one row per block, hour, day, week, month or quarter")
+	flag.StringVar(&settings.Fees.DailyBuyback, "daily-buyback", "0", "the amount of tokens bought back and burned every day")
+	flag.BoolVar(&timestamps, "timestamps", false, "add a unix timestamp column")
+	flag.BoolVar(&heights, "heights", false, "add an estimated block height column")
+	flag.StringVar(&labelsFile, "labels", "", "a csv or yaml file labeling addresses with cohorts, adds a column with the unlocks of each cohort")
 	flag.Parse()
 
-	// read file with the io package
-	file, err := os.Open(genesisFile)
-	if err != nil {
-		fmt.Println("Error opening json file")
+	if format != "csv" && format != "json" && format != "ndjson" && format != "markdown" {
+		fmt.Printf("Unknown format %q\n", format)
 		return
 	}
-	defer file.Close()
-
-	// create a decoder
-	decoder := json.NewDecoder(file)
 
-	// create a map to store the data
-	var genesis map[string]interface{}
+	genesis, err := ReadGenesis(genesisFile)
+	if err != nil {
+		fmt.Println("Error reading genesis file:", err)
+		return
+	}
 
-	// decode the json file into the map
-	err = decoder.Decode(&genesis)
+	// totalSupply here matches the total supply in the genesis.json from the banking module. A good verification that math is correct
+	config, err := settings.Config(genesis.AppState)
 	if err != nil {
-		fmt.Println("Error decoding json")
+		fmt.Println("Error in settings:", err)
 		return
 	}
 
-	// get accounts
-	appState := (genesis["app_state"].(map[string]interface{}))
+	genesis.SetHeights(&config)
 
-	continuousVestingAccounts, delayedVestingAccounts := vestingModule.GetVestingAccounts(appState)
+	var labels labelsModule.Labels
+	if labelsFile != "" {
+		labels, err = labelsModule.Load(labelsFile)
+		if err != nil {
+			fmt.Println("Error reading labels file:", err)
+			return
+		}
 
-	// totalSupply here matches the total supply in the genesis.json from the banking module. A good verification that math is correct
-	totalSupply, vestingOnDays := vestingModule.GetTotalSupplyAndVestingSchedule(appState, continuousVestingAccounts, delayedVestingAccounts)
-	stakedTokens := stakingModule.GetStakedTokens(appState)
-	params, minter := mintModule.GetParamsAndMinter(appState)
+		appState := settings.AppState(genesis.AppState)
+		continuousAccounts, delayedAccounts := vestingModule.GetVestingAccounts(appState)
+		config.CohortVesting = labels.Schedules(vestingModule.GetAccounts(continuousAccounts, delayedAccounts), config.Anchor)
+	}
+
+	// the summary needs a few years of projection even when everything unlocks sooner
+	if format == "markdown" && config.Days == 0 && projection.LastUnlock(*config.VestingOnDays) < SUMMARY_DAYS {
+		config.Days = SUMMARY_DAYS
+	}
 
-	// write the data to a csv file
-	file, err = os.Create(csvStr)
+	projected := projection.Run(config)
+
+	if out == "" {
+		out = csvStr
+		if format != "csv" {
+			out = defaultOutput(format)
+		}
+	}
+
+	// write the data to the output file
+	file, err := os.Create(out)
 	if err != nil {
-		fmt.Println("Error creating csv file")
+		fmt.Println("Error creating output file")
 		return
 	}
 	defer file.Close()
 
-	writer := csv.NewWriter(file)
+	switch format {
+	case "csv":
+		writer := csv.NewWriter(file)
+		options := CSVOptions{Genesis: &genesis, Timestamps: timestamps, Heights: heights}
+		if labels != nil {
+			options.Cohorts = labelsModule.Cohorts(config.CohortVesting)
+			appState := settings.AppState(genesis.AppState)
+			options.CohortBalances = labels.Balances(vestingModule.GetBalances(appState), bondDenom(appState))
+		}
+
+		WriteCSV(writer, projected, options)
+	case "json":
+		err = WriteJSON(file, NewMetadata(genesis, settings, projected), projected)
+	case "ndjson":
+		err = WriteNDJSON(file, NewMetadata(genesis, settings, projected), projected)
+	case "markdown":
+		appState := settings.AppState(genesis.AppState)
+		continuousAccounts, delayedAccounts := vestingModule.GetVestingAccounts(appState)
+		holders := vestingModule.GetHolders(appState, continuousAccounts, delayedAccounts)
+
+		err = WriteMarkdown(file, NewMetadata(genesis, settings, projected), projected, holders)
+	}
+
+	if err != nil {
+		fmt.Println("Error writing output:", err)
+		return
+	}
+	if projected.CapReachedOn >= 0 {
+		fmt.Printf("\nMax supply reached on day %d\n", projected.CapReachedOn)
+	}
 
-	WriteCSV(writer, vestingOnDays, totalSupply, stakedTokens, minter, params)
 	fmt.Printf("\nDone\n")
 }
@@ -5,13 +5,20 @@ import (
 	"encoding/csv"
 	"encoding/json"
 	"io"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"testing"
+	"time"
 
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	mintingTypes "github.com/cosmos/cosmos-sdk/x/mint/types"
 	"github.com/stretchr/testify/assert"
 
+	labelsModule "github.com/brianosaurus/challenge2/labels"
 	mintModule "github.com/brianosaurus/challenge2/mint"
+	"github.com/brianosaurus/challenge2/projection"
 	stakingModule "github.com/brianosaurus/challenge2/staking"
 	vestingModule "github.com/brianosaurus/challenge2/vesting"
 )
@@ -201,6 +208,8 @@ MINTER =
 
 
 func TestWriteCSV(t *testing.T) {
+	vestingModule.TheTime = 1669100000 // make this static for testing
+
 	// create a decoder
 	stringReader := strings.NewReader(MINTER)
 	decoder := json.NewDecoder(stringReader)
@@ -276,12 +285,85 @@ func TestWriteCSV(t *testing.T) {
 	var buf bytes.Buffer
 	bufWriter := io.Writer(&buf)
 	writer := csv.NewWriter(bufWriter)
-	WriteCSV(writer, vestingOnDays, totalSupply, stakedTokens, minter, params)
+	projected := projection.Run(projection.Config{
+		VestingOnDays: vestingOnDays,
+		TotalSupply:   totalSupply,
+		StakedTokens:  stakedTokens,
+		Model:         mintModule.NewStandardModel(params, minter),
+		Fees:          projection.NoFees(),
+		Anchor:        vestingModule.TheTime,
+	})
+	WriteCSV(writer, projected, CSVOptions{})
 
 	bufString := strings.Split(buf.String(), "\n")
 
 	// I reaize this is obnoxiously long ... short on time to do this better
-	assert.Equal(t, "Days Since Genesis Analyzed,Tokens Unvesting,Inflation,Staking Rewards,Circulating Supply,Total Supply", bufString[0])
-	assert.Equal(t, "0,0,0.130000000000000000,0,1240202470400,11582258000000", bufString[1])
-	assert.Equal(t, "815,12295065600,0.132975646103044134,54508050696,11636766050696,11636766050696", bufString[len(bufString)-2])
-}
\ No newline at end of file
+	assert.Equal(t, "Days Since Genesis Analyzed,Date,Tokens Unvesting,Inflation,Staking Rewards,Circulating Supply,Total Supply,Fees Collected,Tokens Burned,Net Supply Change,Liquid,Staked Vested,Staked Unvested,Locked", bufString[0])
+	assert.Equal(t, "start,2022-11-22,0,0.130000000000000000,0,1235009099954,11582258000000,0,0,0,1235008099954,1000000,0,10347248900046", bufString[1])
+	assert.Equal(t, "816,2025-02-15,5180014800,0.134497246686921808,3990009339286,15572267339286,15572267339286,0,0,5736838037,15572266339286,1000000,0,0", bufString[len(bufString)-2])
+
+	// with labels the unlocks of each cohort get a column, the delayed account unlocks on day 85
+	labels := labelsModule.Labels{"umee1agzky2ak6xs5vve3c2wzjtqdq7fwadcgj2mxf9": "team"}
+	cohortVesting := labels.Schedules(vestingModule.GetAccounts(continuousVestingAccounts, delayedVestingAccounts), vestingModule.TheTime)
+	projected = projection.Run(projection.Config{
+		VestingOnDays: vestingOnDays,
+		TotalSupply:   totalSupply,
+		StakedTokens:  stakedTokens,
+		Model:         mintModule.NewStandardModel(params, minter),
+		Fees:          projection.NoFees(),
+		Anchor:        vestingModule.TheTime,
+		CohortVesting: cohortVesting,
+	})
+
+	buf.Reset()
+	WriteCSV(csv.NewWriter(&buf), projected, CSVOptions{Cohorts: labelsModule.Cohorts(cohortVesting)})
+
+	bufString = strings.Split(buf.String(), "\n")
+	assert.True(t, strings.HasSuffix(bufString[0], ",Locked,Tokens Unvesting (team),Tokens Unvesting (unlabeled)"))
+	assert.True(t, strings.HasSuffix(bufString[1], ",0,0"))
+	assert.True(t, strings.HasPrefix(bufString[87], "85,2023-02-15,321577081967,"))
+	assert.True(t, strings.HasSuffix(bufString[87], ",309282000000,12295081967"))
+}
+func TestReadGenesisAndHeader(t *testing.T) {
+	genesisFile := filepath.Join(t.TempDir(), "genesis.json")
+	raw := `{"genesis_time": "2022-11-22T00:00:00Z", "chain_id": "umee-1", "initial_height": "1", "app_state": ` + STAKING_ACCOUNTS + `}`
+	err := os.WriteFile(genesisFile, []byte(raw), 0644)
+	if err != nil {
+		t.Log("Error writing genesis")
+		t.FailNow()
+	}
+
+	genesis, err := ReadGenesis(genesisFile)
+	assert.Nil(t, err)
+	assert.Equal(t, "umee-1", genesis.ChainID)
+	assert.Equal(t, int64(1669075200), genesis.GenesisTime.Unix())
+	assert.Equal(t, 64, len(genesis.Hash))
+	assert.NotNil(t, genesis.AppState["genutil"])
+
+	vestingOnDays := map[int]sdk.Dec{1: sdk.NewDec(1000)}
+	config := projection.Config{
+		VestingOnDays: &vestingOnDays,
+		TotalSupply:   sdk.NewDec(1000000000),
+		StakedTokens:  stakingModule.GetStakedTokens(genesis.AppState),
+		Model:         mintModule.NewStandardModel(mintingTypes.DefaultParams(), mintingTypes.DefaultInitialMinter()),
+		Fees:          projection.NoFees(),
+		Anchor:        1669161600, // a day after genesis
+		BlockTime:     5 * time.Second,
+	}
+	genesis.SetHeights(&config)
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	WriteCSV(writer, projection.Run(config), CSVOptions{Genesis: &genesis, Timestamps: true, Heights: true})
+
+	bufString := strings.Split(buf.String(), "\n")
+	assert.Equal(t, "# Anchor Time,2022-11-23T00:00:00Z", bufString[0])
+	assert.Equal(t, "# Chain ID,umee-1", bufString[1])
+	assert.Equal(t, "# Genesis Hash,"+genesis.Hash, bufString[2])
+	assert.True(t, strings.HasPrefix(bufString[3], "Days Since Genesis Analyzed,Date,Timestamp,Block Height,Tokens Unvesting,"))
+
+	// the start is a day of 5 second blocks after genesis, a day before the end of day 0 on the same date
+	assert.True(t, strings.HasPrefix(bufString[4], "start,2022-11-23,1669161600,17281,0,"))
+	assert.True(t, strings.HasPrefix(bufString[5], "0,2022-11-23,1669248000,34561,0,"))
+	assert.True(t, strings.HasPrefix(bufString[6], "1,2022-11-24,1669334400,51841,1000,"))
+}
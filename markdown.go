@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/brianosaurus/challenge2/projection"
+	vestingModule "github.com/brianosaurus/challenge2/vesting"
+)
+
+const (
+	// the markdown summary projects at least this far so every year in SUMMARY_YEARS is covered
+	SUMMARY_DAYS    = 4*365 + 1
+	SUMMARY_UNLOCKS = 5
+	SUMMARY_HOLDERS = 10
+)
+
+var SUMMARY_YEARS = []int{1, 2, 4}
+
+// WriteMarkdown writes the key figures of the projection as markdown tables that can be pasted into
+// a forum post or a governance proposal
+func WriteMarkdown(writer io.Writer, metadata Metadata, projected projection.Projection, holders []vestingModule.Holder) error {
+	if len(projected.Rows) == 0 {
+		return fmt.Errorf("the projection has no rows")
+	}
+
+	first := projected.Rows[0]
+	genesisSupply := first.TotalSupply
+	date := func(row projection.Row) string {
+		return projected.Date(row).Format("2006-01-02")
+	}
+
+	var b strings.Builder
+
+	title := "Tokenomics Summary"
+	if metadata.ChainID != "" {
+		title += " for " + metadata.ChainID
+	}
+
+	fmt.Fprintf(&b, "# %s\n\n", title)
+	fmt.Fprintf(&b, "Projected from %s with the %s inflation model", date(first), metadata.InflationModel)
+	if metadata.GenesisHash != "" {
+		fmt.Fprintf(&b, " from genesis `%s`", metadata.GenesisHash)
+	}
+	b.WriteString(".\n\n")
+
+	b.WriteString("## Supply\n\n")
+	b.WriteString("| | Date | Total Supply | Circulating Supply |\n")
+	b.WriteString("|---|---|---:|---:|\n")
+	fmt.Fprintf(&b, "| Genesis / now | %s | %s | %s |\n", date(first), withCommas(first.TotalSupply), withCommas(first.CirculatingSupply))
+
+	for _, years := range SUMMARY_YEARS {
+		label := fmt.Sprintf("%d year", years)
+		if years > 1 {
+			label += "s"
+		}
+
+		row, ok := rowOnDay(projected, years*365)
+		if !ok {
+			fmt.Fprintf(&b, "| %s | | beyond the projection | |\n", label)
+			continue
+		}
+
+		fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", label, date(row), withCommas(row.TotalSupply), withCommas(row.CirculatingSupply))
+	}
+
+	peak := first
+	for _, row := range projected.Rows {
+		if row.Inflation.GT(peak.Inflation) {
+			peak = row
+		}
+	}
+
+	fmt.Fprintf(&b, "\nPeak inflation is **%s** on %s.", percentOf(peak.Inflation, sdk.OneDec()), date(peak))
+	if metadata.CapReachedOn != nil {
+		fmt.Fprintf(&b, " The max supply is reached on day %d.", *metadata.CapReachedOn)
+	}
+	b.WriteString("\n\n")
+
+	unlocks := []projection.Row{}
+	for _, row := range projected.Rows {
+		if row.TokensUnvesting.IsPositive() {
+			unlocks = append(unlocks, row)
+		}
+	}
+
+	sort.SliceStable(unlocks, func(i, j int) bool {
+		return unlocks[i].TokensUnvesting.GT(unlocks[j].TokensUnvesting)
+	})
+
+	if len(unlocks) > SUMMARY_UNLOCKS {
+		unlocks = unlocks[:SUMMARY_UNLOCKS]
+	}
+
+	b.WriteString("## Largest Unlocks\n\n")
+	if len(unlocks) == 0 {
+		b.WriteString("Nothing unlocks during the projection.\n\n")
+	} else {
+		if hasPeriods(projected) {
+			b.WriteString("| Date | Period | Tokens Unlocking | Share of Genesis Supply |\n")
+			b.WriteString("|---|---|---:|---:|\n")
+		} else {
+			b.WriteString("| Date | Tokens Unlocking | Share of Genesis Supply |\n")
+			b.WriteString("|---|---:|---:|\n")
+		}
+
+		for _, row := range unlocks {
+			fmt.Fprintf(&b, "| %s | ", date(row))
+			if hasPeriods(projected) {
+				fmt.Fprintf(&b, "%s | ", row.Label())
+			}
+
+			fmt.Fprintf(&b, "%s | %s |\n", withCommas(row.TokensUnvesting), percentOf(row.TokensUnvesting, genesisSupply))
+		}
+
+		b.WriteString("\n")
+	}
+
+	if len(holders) > SUMMARY_HOLDERS {
+		holders = holders[:SUMMARY_HOLDERS]
+	}
+
+	b.WriteString("## Top Holders\n\n")
+	b.WriteString("| # | Address | Amount | Share of Genesis Supply | Vesting |\n")
+	b.WriteString("|---:|---|---:|---:|---|\n")
+
+	for i, holder := range holders {
+		vesting := ""
+		if holder.Vesting {
+			vesting = "yes"
+		}
+
+		fmt.Fprintf(&b, "| %d | `%s` | %s | %s | %s |\n", i+1, holder.Address, withCommas(holder.Amount), percentOf(holder.Amount, genesisSupply), vesting)
+	}
+
+	_, err := io.WriteString(writer, b.String())
+	return err
+}
+
+// rowOnDay returns the first row that ends on or after day
+func rowOnDay(projected projection.Projection, day int) (projection.Row, bool) {
+	for _, row := range projected.Rows {
+		if row.Day >= day {
+			return row, true
+		}
+	}
+
+	return projection.Row{}, false
+}
+
+// withCommas writes a whole amount with thousands separators, 1234567 is 1,234,567
+func withCommas(amount sdk.Dec) string {
+	str := amount.RoundInt().String()
+
+	sign := ""
+	if strings.HasPrefix(str, "-") {
+		sign, str = "-", str[1:]
+	}
+
+	for i := len(str) - 3; i > 0; i -= 3 {
+		str = str[:i] + "," + str[i:]
+	}
+
+	return sign + str
+}
+
+func percentOf(amount sdk.Dec, total sdk.Dec) string {
+	if total.IsZero() {
+		return "0.00%"
+	}
+
+	return fmt.Sprintf("%.2f%%", amount.Quo(total).MustFloat64()*100)
+}
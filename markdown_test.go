@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	mintingTypes "github.com/cosmos/cosmos-sdk/x/mint/types"
+	"github.com/stretchr/testify/assert"
+
+	mintModule "github.com/brianosaurus/challenge2/mint"
+	"github.com/brianosaurus/challenge2/projection"
+	vestingModule "github.com/brianosaurus/challenge2/vesting"
+)
+
+func TestWriteMarkdown(t *testing.T) {
+	vestingOnDays := map[int]sdk.Dec{0: sdk.NewDec(1000), 30: sdk.NewDec(250000000), 400: sdk.NewDec(2000)}
+	projected := projection.Run(projection.Config{
+		VestingOnDays: &vestingOnDays,
+		TotalSupply:   sdk.NewDec(1000000000),
+		StakedTokens:  sdk.NewDec(500000000),
+		Model:         mintModule.NewStandardModel(mintingTypes.DefaultParams(), mintingTypes.DefaultInitialMinter()),
+		Fees:          projection.NoFees(),
+		Anchor:        1669100000,
+		Days:          800,
+	})
+
+	holders := []vestingModule.Holder{
+		{Address: "umee1big", Amount: sdk.NewDec(600000000), Vesting: true},
+		{Address: "umee1small", Amount: sdk.NewDec(400000000)},
+	}
+
+	var buf bytes.Buffer
+	err := WriteMarkdown(&buf, Metadata{ChainID: "umee-1", InflationModel: "cosmos"}, projected, holders)
+	assert.Nil(t, err)
+
+	markdown := buf.String()
+	lines := strings.Split(markdown, "\n")
+
+	assert.Equal(t, "# Tokenomics Summary for umee-1", lines[0])
+	assert.Contains(t, markdown, "| Genesis / now | 2022-11-22 | 1,000,000,000 | 749,997,000 |")
+	assert.Contains(t, markdown, "| 1 year | 2023-11-22 |")
+	assert.Contains(t, markdown, "| 2 years | 2024-11-21 |")
+	assert.Contains(t, markdown, "| 4 years | | beyond the projection | |")
+	assert.Contains(t, markdown, "Peak inflation is **")
+
+	// the biggest unlock comes first
+	assert.Contains(t, markdown, "| Date | Tokens Unlocking | Share of Genesis Supply |\n|---|---:|---:|\n| 2022-12-22 | 250,000,000 | 25.00% |\n")
+	assert.Contains(t, markdown, "| 1 | `umee1big` | 600,000,000 | 60.00% | yes |")
+	assert.Contains(t, markdown, "| 2 | `umee1small` | 400,000,000 | 40.00% |  |")
+
+	assert.Equal(t, "1,234,567", withCommas(sdk.NewDec(1234567)))
+	assert.Equal(t, "-123", withCommas(sdk.NewDec(-123)))
+	assert.Equal(t, "999", withCommas(sdk.NewDec(999)))
+}
@@ -0,0 +1,454 @@
+package mint
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	mintingTypes "github.com/cosmos/cosmos-sdk/x/mint/types"
+)
+
+const (
+	BLOCKS_PER_YEAR = (60 / SECONDS_PER_BLOCK) * 60 * 24 * 365
+	HOURS_PER_YEAR  = 24 * 365
+)
+
+// InflationModel is the schedule a chain uses to mint new tokens. The simulation advances
+// the model with Step and then mints BlockProvision once for every block in that step, either
+// block by block or all blocks of the step at once with Provisions.
+type InflationModel interface {
+	// Step moves the model forward by elapsed time and recalculates the provisions using
+	// the bonded ratio and total supply at that point in time
+	Step(elapsed time.Duration, bondedRatio sdk.Dec, totalSupply sdk.Int)
+	// Inflation returns the current annual inflation rate
+	Inflation() sdk.Dec
+	// BlockProvision returns the number of tokens minted by the next block
+	BlockProvision() sdk.Int
+}
+
+// StandardModel is the x/mint model. Inflation moves toward GoalBonded by at most
+// InflationRateChange per year and is bounded by InflationMin and InflationMax.
+type StandardModel struct {
+	Params mintingTypes.Params
+	Minter mintingTypes.Minter
+}
+
+func NewStandardModel(params mintingTypes.Params, minter mintingTypes.Minter) *StandardModel {
+	return &StandardModel{Params: params, Minter: minter}
+}
+
+func (m *StandardModel) Step(_ time.Duration, bondedRatio sdk.Dec, totalSupply sdk.Int) {
+	m.Minter.Inflation = m.Minter.NextInflationRate(m.Params, bondedRatio)
+	m.Minter.AnnualProvisions = m.Minter.NextAnnualProvisions(m.Params, totalSupply)
+}
+
+func (m *StandardModel) Inflation() sdk.Dec {
+	return m.Minter.Inflation
+}
+
+func (m *StandardModel) BlockProvision() sdk.Int {
+	return m.Minter.BlockProvision(m.Params).Amount
+}
+
+// EpochReductionModel mints a fixed amount every epoch and multiplies that amount by
+// ReductionFactor every ReductionPeriodInEpochs epochs (Osmosis "thirdening", Evmos decay).
+// TailProvisions are minted every epoch on top of the decaying amount and never reduce.
+type EpochReductionModel struct {
+	EpochProvisions         sdk.Dec
+	TailProvisions          sdk.Dec
+	EpochDuration           time.Duration
+	ReductionPeriodInEpochs int64
+	ReductionFactor         sdk.Dec
+	BlockTime               time.Duration
+
+	epochsSinceReduction int64
+	sinceEpoch           time.Duration
+	totalSupply          sdk.Int
+}
+
+func (m *EpochReductionModel) Step(elapsed time.Duration, _ sdk.Dec, totalSupply sdk.Int) {
+	m.totalSupply = totalSupply
+	m.sinceEpoch += elapsed
+
+	for m.sinceEpoch >= m.EpochDuration {
+		m.sinceEpoch -= m.EpochDuration
+		m.epochsSinceReduction++
+
+		if m.ReductionPeriodInEpochs > 0 && m.epochsSinceReduction >= m.ReductionPeriodInEpochs {
+			m.epochsSinceReduction = 0
+			m.EpochProvisions = m.EpochProvisions.Mul(m.ReductionFactor)
+		}
+	}
+}
+
+func (m *EpochReductionModel) Inflation() sdk.Dec {
+	if m.totalSupply.IsNil() || !m.totalSupply.IsPositive() {
+		return sdk.ZeroDec()
+	}
+
+	epochsPerYear := int64((time.Hour * HOURS_PER_YEAR) / m.EpochDuration)
+	annualProvisions := m.EpochProvisions.Add(m.TailProvisions).MulInt64(epochsPerYear)
+
+	return annualProvisions.QuoInt(m.totalSupply)
+}
+
+func (m *EpochReductionModel) BlockProvision() sdk.Int {
+	blockTime := m.BlockTime
+	if blockTime == 0 {
+		blockTime = time.Second * SECONDS_PER_BLOCK
+	}
+
+	blocksPerEpoch := int64(m.EpochDuration / blockTime)
+	if blocksPerEpoch == 0 {
+		blocksPerEpoch = 1
+	}
+
+	return m.EpochProvisions.Add(m.TailProvisions).QuoInt64(blocksPerEpoch).TruncateInt()
+}
+
+// CappedModel stops another model from minting past MaxSupply. When TaperSupply is set the
+// provisions are scaled down linearly once the total supply is within TaperSupply of the cap
+// so minting slows to a halt instead of stopping abruptly.
+type CappedModel struct {
+	Model       InflationModel
+	MaxSupply   sdk.Int
+	TaperSupply sdk.Int
+
+	totalSupply sdk.Int
+}
+
+func NewCappedModel(model InflationModel, maxSupply sdk.Int, taperSupply sdk.Int) *CappedModel {
+	return &CappedModel{Model: model, MaxSupply: maxSupply, TaperSupply: taperSupply}
+}
+
+func (m *CappedModel) Step(elapsed time.Duration, bondedRatio sdk.Dec, totalSupply sdk.Int) {
+	m.Model.Step(elapsed, bondedRatio, totalSupply)
+	m.totalSupply = totalSupply
+}
+
+// Inflation is the inflation of the underlying model scaled down by the taper
+func (m *CappedModel) Inflation() sdk.Dec {
+	return m.Model.Inflation().Mul(m.taper())
+}
+
+// BlockProvision keeps track of what has been minted since the last step so the cap is never
+// crossed in the middle of a step
+func (m *CappedModel) BlockProvision() sdk.Int {
+	if m.totalSupply.IsNil() {
+		return sdk.ZeroInt()
+	}
+
+	provision := sdk.NewDecFromInt(m.Model.BlockProvision()).Mul(m.taper()).TruncateInt()
+	headroom := m.MaxSupply.Sub(m.totalSupply)
+
+	if provision.GT(headroom) {
+		provision = headroom
+	}
+
+	if provision.IsNegative() {
+		provision = sdk.ZeroInt()
+	}
+
+	m.totalSupply = m.totalSupply.Add(provision)
+
+	return provision
+}
+
+// Reached returns true once the total supply has hit the cap
+func (m *CappedModel) Reached() bool {
+	return !m.totalSupply.IsNil() && m.totalSupply.GTE(m.MaxSupply)
+}
+
+// the fraction of the provisions that are still minted, between zero and one
+func (m *CappedModel) taper() sdk.Dec {
+	if m.totalSupply.IsNil() {
+		return sdk.OneDec()
+	}
+
+	headroom := m.MaxSupply.Sub(m.totalSupply)
+	if !headroom.IsPositive() {
+		return sdk.ZeroDec()
+	}
+
+	if m.TaperSupply.IsNil() || !m.TaperSupply.IsPositive() || headroom.GTE(m.TaperSupply) {
+		return sdk.OneDec()
+	}
+
+	return sdk.NewDecFromInt(headroom).QuoInt(m.TaperSupply)
+}
+
+// Phase is one period of a FixedPhaseModel
+type Phase struct {
+	Inflation sdk.Dec
+	Duration  time.Duration
+}
+
+// FixedPhaseModel mints a fixed inflation for each phase (Juno). The annual provisions of
+// a phase are fixed from the total supply at the moment the phase starts. Once the last
+// phase is over minting stops.
+type FixedPhaseModel struct {
+	Phases        []Phase
+	BlocksPerYear int64
+
+	phase            int
+	sinceStart       time.Duration
+	annualProvisions sdk.Dec
+}
+
+func (m *FixedPhaseModel) Step(elapsed time.Duration, _ sdk.Dec, totalSupply sdk.Int) {
+	if m.annualProvisions.IsNil() {
+		m.startPhase(totalSupply)
+	}
+
+	m.sinceStart += elapsed
+
+	for m.phase < len(m.Phases) && m.sinceStart >= m.Phases[m.phase].Duration {
+		m.sinceStart -= m.Phases[m.phase].Duration
+		m.phase++
+		m.startPhase(totalSupply)
+	}
+}
+
+func (m *FixedPhaseModel) startPhase(totalSupply sdk.Int) {
+	m.annualProvisions = m.Inflation().MulInt(totalSupply)
+}
+
+func (m *FixedPhaseModel) Inflation() sdk.Dec {
+	if m.phase >= len(m.Phases) {
+		return sdk.ZeroDec()
+	}
+
+	return m.Phases[m.phase].Inflation
+}
+
+func (m *FixedPhaseModel) BlockProvision() sdk.Int {
+	if m.annualProvisions.IsNil() {
+		return sdk.ZeroInt()
+	}
+
+	blocksPerYear := m.BlocksPerYear
+	if blocksPerYear == 0 {
+		blocksPerYear = BLOCKS_PER_YEAR
+	}
+
+	return m.annualProvisions.QuoInt64(blocksPerYear).TruncateInt()
+}
+
+// Provisions returns what the next blocks mint together, the same as calling BlockProvision that many times.
+// Block provisions only change in Step, so most models mint them all at once.
+func Provisions(model InflationModel, blocks int64) sdk.Int {
+	switch m := model.(type) {
+	case *StandardModel, *EpochReductionModel, *FixedPhaseModel:
+		return m.BlockProvision().MulRaw(blocks)
+	case *CappedModel:
+		return m.provisions(blocks)
+	}
+
+	minted := sdk.ZeroInt()
+	for i := int64(0); i < blocks; i++ {
+		minted = minted.Add(model.BlockProvision())
+	}
+
+	return minted
+}
+
+// provisions mints blocks at once while the taper and the cap can not be reached on the way, block by block after that
+func (m *CappedModel) provisions(blocks int64) sdk.Int {
+	if m.totalSupply.IsNil() {
+		return sdk.ZeroInt()
+	}
+
+	if m.taper().Equal(sdk.OneDec()) {
+		batch := Provisions(m.Model, blocks)
+		taperFrom := m.MaxSupply
+		if !m.TaperSupply.IsNil() && m.TaperSupply.IsPositive() {
+			taperFrom = m.MaxSupply.Sub(m.TaperSupply)
+		}
+
+		if m.totalSupply.Add(batch).LTE(taperFrom) {
+			m.totalSupply = m.totalSupply.Add(batch)
+			return batch
+		}
+	}
+
+	minted := sdk.ZeroInt()
+	for i := int64(0); i < blocks; i++ {
+		minted = minted.Add(m.BlockProvision())
+	}
+
+	return minted
+}
+
+// SetBlockTime changes how long the blocks of time based models take. Epoch models mint by time so a
+// different block time changes the provisions of each block, not of each epoch. x/mint and Juno mint a
+// 1/blocks_per_year share of the annual provisions every block, there the block time changes how much
+// is minted in a year and the model stays as it is.
+func SetBlockTime(model InflationModel, blockTime time.Duration) {
+	switch m := model.(type) {
+	case *EpochReductionModel:
+		m.BlockTime = blockTime
+	case *CappedModel:
+		SetBlockTime(m.Model, blockTime)
+	}
+}
+
+// BlockTime is how long the model expects a block to take, from blocks_per_year for the models that have one
+func BlockTime(model InflationModel) time.Duration {
+	blocksPerYear := int64(BLOCKS_PER_YEAR)
+
+	switch m := model.(type) {
+	case *StandardModel:
+		if m.Params.BlocksPerYear > 0 {
+			blocksPerYear = int64(m.Params.BlocksPerYear)
+		}
+	case *FixedPhaseModel:
+		if m.BlocksPerYear > 0 {
+			blocksPerYear = m.BlocksPerYear
+		}
+	case *EpochReductionModel:
+		if m.BlockTime > 0 {
+			return m.BlockTime
+		}
+	case *CappedModel:
+		return BlockTime(m.Model)
+	}
+
+	return time.Hour * HOURS_PER_YEAR / time.Duration(blocksPerYear)
+}
+
+// the yearly inflation of each Juno phase, the minter in genesis says which one it starts in
+var junoPhases = []string{"0.40", "0.20", "0.10", "0.09", "0.08", "0.07", "0.06", "0.05", "0.04", "0.03", "0.02", "0.01"}
+
+var epochDurations = map[string]time.Duration{
+	"hour": time.Hour,
+	"day":  time.Hour * 24,
+	"week": time.Hour * 24 * 7,
+}
+
+// GetInflationModel picks the inflation model for the genesis. profile is one of
+// cosmos, osmosis, evmos or juno. An empty profile detects the model from the app state.
+func GetInflationModel(appState map[string]interface{}, profile string) InflationModel {
+	if profile == "" {
+		profile = DetectProfile(appState)
+	}
+
+	switch profile {
+	case "cosmos":
+		params, minter := GetParamsAndMinter(appState)
+		return NewStandardModel(params, minter)
+	case "osmosis":
+		return getOsmosisModel(appState)
+	case "evmos":
+		return getEvmosModel(appState)
+	case "juno":
+		return getJunoModel(appState)
+	}
+
+	panic(fmt.Sprintln("Unknown inflation profile", profile))
+}
+
+// DetectProfile looks at the shape of the mint state to figure out which chain it is from
+func DetectProfile(appState map[string]interface{}) string {
+	if inflation, ok := appState["inflation"].(map[string]interface{}); ok {
+		if _, ok := inflation["epochs_per_period"]; ok {
+			return "evmos"
+		}
+	}
+
+	mint := (appState["mint"]).(map[string]interface{})
+	minterJson := (mint["minter"]).(map[string]interface{})
+
+	if _, ok := minterJson["epoch_provisions"]; ok {
+		return "osmosis"
+	}
+
+	if _, ok := minterJson["phase"]; ok {
+		return "juno"
+	}
+
+	return "cosmos"
+}
+
+func getOsmosisModel(appState map[string]interface{}) *EpochReductionModel {
+	mint := (appState["mint"]).(map[string]interface{})
+	minterJson := (mint["minter"]).(map[string]interface{})
+	paramsJson := (mint["params"]).(map[string]interface{})
+
+	epochDuration, ok := epochDurations[paramsJson["epoch_identifier"].(string)]
+	if !ok {
+		panic(fmt.Sprintln("Unknown epoch identifier", paramsJson["epoch_identifier"]))
+	}
+
+	return &EpochReductionModel{
+		EpochProvisions:         sdk.MustNewDecFromStr(minterJson["epoch_provisions"].(string)),
+		TailProvisions:          sdk.ZeroDec(),
+		EpochDuration:           epochDuration,
+		ReductionPeriodInEpochs: mustParseInt(paramsJson["reduction_period_in_epochs"].(string)),
+		ReductionFactor:         sdk.MustNewDecFromStr(paramsJson["reduction_factor"].(string)),
+	}
+}
+
+// evmos mints a(1 - r)^period + c whole tokens each period, spread over the epochs of the period
+func getEvmosModel(appState map[string]interface{}) *EpochReductionModel {
+	inflation := (appState["inflation"]).(map[string]interface{})
+	paramsJson := (inflation["params"]).(map[string]interface{})
+	calculation := (paramsJson["exponential_calculation"]).(map[string]interface{})
+
+	epochDuration, ok := epochDurations[inflation["epoch_identifier"].(string)]
+	if !ok {
+		panic(fmt.Sprintln("Unknown epoch identifier", inflation["epoch_identifier"]))
+	}
+
+	epochsPerPeriod := mustParseInt(inflation["epochs_per_period"].(string))
+	period := mustParseInt(inflation["period"].(string))
+	reductionFactor := sdk.OneDec().Sub(sdk.MustNewDecFromStr(calculation["r"].(string)))
+
+	// a and c are in whole tokens, evmos has 18 decimals
+	decimals := sdk.NewDec(10).Power(18)
+	a := sdk.MustNewDecFromStr(calculation["a"].(string)).Mul(decimals)
+	c := sdk.MustNewDecFromStr(calculation["c"].(string)).Mul(decimals)
+
+	return &EpochReductionModel{
+		EpochProvisions:         a.Mul(reductionFactor.Power(uint64(period))).QuoInt64(epochsPerPeriod),
+		TailProvisions:          c.QuoInt64(epochsPerPeriod),
+		EpochDuration:           epochDuration,
+		ReductionPeriodInEpochs: epochsPerPeriod,
+		ReductionFactor:         reductionFactor,
+	}
+}
+
+func getJunoModel(appState map[string]interface{}) *FixedPhaseModel {
+	mint := (appState["mint"]).(map[string]interface{})
+	minterJson := (mint["minter"]).(map[string]interface{})
+
+	// juno phases are numbered from 1
+	phase := int(mustParseInt(minterJson["phase"].(string))) - 1
+
+	phases := make([]Phase, 0, len(junoPhases))
+	for i := phase; i >= 0 && i < len(junoPhases); i++ {
+		phases = append(phases, Phase{
+			Inflation: sdk.MustNewDecFromStr(junoPhases[i]),
+			Duration:  time.Hour * HOURS_PER_YEAR,
+		})
+	}
+
+	model := &FixedPhaseModel{Phases: phases}
+
+	// like x/mint juno mints a 1/blocks_per_year share of the annual provisions every block
+	paramsJson, _ := mint["params"].(map[string]interface{})
+	if blocksPerYear, ok := paramsJson["blocks_per_year"].(string); ok {
+		model.BlocksPerYear = mustParseInt(blocksPerYear)
+	}
+
+	return model
+}
+
+func mustParseInt(str string) int64 {
+	value, err := strconv.ParseInt(str, 10, 64)
+	if err != nil {
+		panic(fmt.Sprintln("Error parsing integer", err))
+	}
+
+	return value
+}
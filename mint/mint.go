@@ -22,7 +22,12 @@ func GetParamsAndMinter(appState map[string]interface{}) (mintingTypes.Params, m
 		InflationMax:        sdk.MustNewDecFromStr(paramsJson["inflation_max"].(string)),
 		InflationMin:        sdk.MustNewDecFromStr(paramsJson["inflation_min"].(string)),
 		GoalBonded:          sdk.MustNewDecFromStr(paramsJson["goal_bonded"].(string)),
-		BlocksPerYear:       uint64((60 / SECONDS_PER_BLOCK) * 60 * 24 * 365), // change this as we're assuming 5 second blocks
+		BlocksPerYear:       uint64((60 / SECONDS_PER_BLOCK) * 60 * 24 * 365), // 5 second blocks when genesis does not say
+	}
+
+	// the chain mints a 1/blocks_per_year share of the annual provisions every block however fast blocks really are
+	if blocksPerYear, ok := paramsJson["blocks_per_year"].(string); ok {
+		params.BlocksPerYear = uint64(mustParseInt(blocksPerYear))
 	}
 
 	minter := mintingTypes.Minter{
@@ -32,3 +37,16 @@ func GetParamsAndMinter(appState map[string]interface{}) (mintingTypes.Params, m
 
 	return params, minter
 }
+
+// GetInflation returns the inflation of the minter in genesis, false when the mint module has none (e.g. osmosis)
+func GetInflation(appState map[string]interface{}) (sdk.Dec, bool) {
+	mint, _ := appState["mint"].(map[string]interface{})
+	minter, _ := mint["minter"].(map[string]interface{})
+
+	inflation, ok := minter["inflation"].(string)
+	if !ok {
+		return sdk.Dec{}, false
+	}
+
+	return sdk.MustNewDecFromStr(inflation), true
+}
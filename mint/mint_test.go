@@ -4,8 +4,10 @@ import (
 	"encoding/json"
 	"strings"
 	"testing"
+	"time"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	mintingTypes "github.com/cosmos/cosmos-sdk/x/mint/types"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -31,6 +33,39 @@ MINTER =
 		}
 	}
 }`
+
+	OSMOSIS_MINT =
+`{
+	"mint": {
+		"minter": {
+			"epoch_provisions": "17280000.000000000000000000"
+		},
+		"params": {
+			"mint_denom": "uosmo",
+			"genesis_epoch_provisions": "17280000.000000000000000000",
+			"epoch_identifier": "day",
+			"reduction_period_in_epochs": "2",
+			"reduction_factor": "0.500000000000000000"
+		}
+	}
+}`
+
+	JUNO_MINT =
+`{
+	"mint": {
+		"minter": {
+			"inflation": "0.200000000000000000",
+			"phase": "2",
+			"start_phase_block": "1",
+			"annual_provisions": "0.000000000000000000",
+			"target_supply": "0"
+		},
+		"params": {
+			"mint_denom": "ujuno",
+			"blocks_per_year": "5048093"
+		}
+	}
+}`
 	)
 
 func TestGetParamsAndMinter(t *testing.T) {
@@ -75,8 +110,13 @@ func TestGetParamsAndMinter(t *testing.T) {
 	assert.Equal(t, inflationMin, params.InflationMin)
 	assert.Equal(t, goalBonded, params.GoalBonded)
 
-	// 5 second blocks as opposed to what is in genesis.json
-	assert.Equal(t, uint64((60 / SECONDS_PER_BLOCK) * 60 * 24 * 365), params.BlocksPerYear) 
+	// what is in genesis.json, the real block time does not change it
+	assert.Equal(t, uint64(4360000), params.BlocksPerYear)
+
+	// 5 second blocks when genesis has none
+	delete(minterJson["mint"].(map[string]interface{})["params"].(map[string]interface{}), "blocks_per_year")
+	params, _ = GetParamsAndMinter(minterJson)
+	assert.Equal(t, uint64((60 / SECONDS_PER_BLOCK) * 60 * 24 * 365), params.BlocksPerYear)
 
 	minterJson = minterJson["mint"].(map[string]interface{})["minter"].(map[string]interface{})
 	inflation := sdk.MustNewDecFromStr(minterJson["inflation"].(string))
@@ -84,4 +124,129 @@ func TestGetParamsAndMinter(t *testing.T) {
 
 	assert.Equal(t, inflation, minter.Inflation)
 	assert.Equal(t, annualProvisions, minter.AnnualProvisions)
-}
\ No newline at end of file
+}
+func TestGetInflationModel(t *testing.T) {
+	var appState = make(map[string]interface{})
+
+	err := json.Unmarshal([]byte(OSMOSIS_MINT), &appState)
+	if err != nil {
+		t.Log("Error decoding json")
+		t.FailNow()
+	}
+
+	assert.Equal(t, "osmosis", DetectProfile(appState))
+
+	model := GetInflationModel(appState, "").(*EpochReductionModel)
+	totalSupply := sdk.NewInt(1000000000)
+
+	// a day is 17280 blocks
+	model.Step(time.Hour, sdk.ZeroDec(), totalSupply)
+	assert.Equal(t, sdk.NewInt(1000), model.BlockProvision())
+	assert.Equal(t, sdk.MustNewDecFromStr("6.3072"), model.Inflation())
+
+	// two epochs later the provisions have been reduced
+	model.Step(time.Hour*48, sdk.ZeroDec(), totalSupply)
+	assert.Equal(t, sdk.NewInt(500), model.BlockProvision())
+
+	appState = make(map[string]interface{})
+
+	err = json.Unmarshal([]byte(JUNO_MINT), &appState)
+	if err != nil {
+		t.Log("Error decoding json")
+		t.FailNow()
+	}
+
+	assert.Equal(t, "juno", DetectProfile(appState))
+
+	phased := GetInflationModel(appState, "").(*FixedPhaseModel)
+
+	// starts in phase 2
+	phased.Step(time.Hour, sdk.ZeroDec(), totalSupply)
+	assert.Equal(t, sdk.MustNewDecFromStr("0.20"), phased.Inflation())
+	// with the blocks_per_year of genesis
+	assert.Equal(t, int64(5048093), phased.BlocksPerYear)
+	assert.Equal(t, sdk.NewInt(200000000).QuoRaw(5048093), phased.BlockProvision())
+
+	phased.Step(time.Hour*HOURS_PER_YEAR, sdk.ZeroDec(), totalSupply)
+	assert.Equal(t, sdk.MustNewDecFromStr("0.10"), phased.Inflation())
+}
+
+func TestCappedModel(t *testing.T) {
+	model := &FixedPhaseModel{Phases: []Phase{{Inflation: sdk.OneDec(), Duration: time.Hour * HOURS_PER_YEAR}}}
+	capped := NewCappedModel(model, sdk.NewInt(1000000000), sdk.NewInt(1000000))
+
+	// far away from the cap nothing changes
+	capped.Step(time.Hour, sdk.ZeroDec(), sdk.NewInt(100000000))
+	assert.Equal(t, sdk.OneDec(), capped.Inflation())
+	assert.Equal(t, int64(15), capped.BlockProvision().Int64())
+	assert.False(t, capped.Reached())
+
+	// half way through the taper the provisions are halved
+	capped.Step(time.Hour, sdk.ZeroDec(), sdk.NewInt(999500000))
+	assert.Equal(t, sdk.MustNewDecFromStr("0.5"), capped.Inflation())
+	assert.Equal(t, int64(7), capped.BlockProvision().Int64())
+
+	// right before the cap the taper has almost stopped minting
+	capped.Step(time.Hour, sdk.ZeroDec(), sdk.NewInt(999999995))
+	assert.Equal(t, int64(0), capped.BlockProvision().Int64())
+
+	// without a taper the last block can only mint what is left
+	capped.TaperSupply = sdk.ZeroInt()
+	capped.Step(time.Hour, sdk.ZeroDec(), sdk.NewInt(999999995))
+	assert.Equal(t, int64(5), capped.BlockProvision().Int64())
+	assert.True(t, capped.Reached())
+	assert.Equal(t, int64(0), capped.BlockProvision().Int64())
+}
+
+func TestProvisions(t *testing.T) {
+	newCapped := func() *CappedModel {
+		model := &FixedPhaseModel{Phases: []Phase{{Inflation: sdk.OneDec(), Duration: time.Hour * HOURS_PER_YEAR}}}
+		return NewCappedModel(model, sdk.NewInt(1000000000), sdk.NewInt(1000000))
+	}
+
+	// minting at once is the same as block by block, also when the taper and the cap are reached on the way
+	for _, supply := range []int64{100000000, 998990000, 999000000, 999999000} {
+		once, byBlock := newCapped(), newCapped()
+		once.Step(time.Hour, sdk.ZeroDec(), sdk.NewInt(supply))
+		byBlock.Step(time.Hour, sdk.ZeroDec(), sdk.NewInt(supply))
+
+		minted := sdk.ZeroInt()
+		for i := 0; i < 720; i++ {
+			minted = minted.Add(byBlock.BlockProvision())
+		}
+
+		assert.Equal(t, minted, Provisions(once, 720), supply)
+		assert.Equal(t, byBlock.Reached(), once.Reached(), supply)
+	}
+
+	model := &FixedPhaseModel{Phases: []Phase{{Inflation: sdk.OneDec(), Duration: time.Hour * HOURS_PER_YEAR}}}
+	model.Step(time.Hour, sdk.ZeroDec(), sdk.NewInt(100000000))
+	assert.Equal(t, model.BlockProvision().MulRaw(720), Provisions(model, 720))
+	assert.True(t, Provisions(model, 0).IsZero())
+}
+
+func TestBlockTime(t *testing.T) {
+	params, minter := mintingTypes.DefaultParams(), mintingTypes.DefaultInitialMinter()
+	params.BlocksPerYear = 4360000
+
+	// x/mint keeps the blocks_per_year of genesis whatever the block time
+	standard := NewStandardModel(params, minter)
+	SetBlockTime(standard, 6*time.Second)
+	assert.Equal(t, uint64(4360000), standard.Params.BlocksPerYear)
+	assert.Equal(t, time.Hour*HOURS_PER_YEAR/4360000, BlockTime(standard))
+
+	phased := &FixedPhaseModel{}
+	SetBlockTime(phased, 6*time.Second)
+	assert.Equal(t, int64(0), phased.BlocksPerYear)
+	assert.Equal(t, time.Second*SECONDS_PER_BLOCK, BlockTime(phased))
+
+	// epoch models spread each epoch over the blocks in it
+	epoch := &EpochReductionModel{EpochProvisions: sdk.NewDec(17280), TailProvisions: sdk.ZeroDec(), EpochDuration: time.Hour * 24}
+	assert.Equal(t, time.Second*SECONDS_PER_BLOCK, BlockTime(epoch))
+	assert.Equal(t, sdk.NewInt(1), epoch.BlockProvision())
+
+	capped := NewCappedModel(epoch, sdk.NewInt(1000000), sdk.Int{})
+	SetBlockTime(capped, 10*time.Second)
+	assert.Equal(t, 10*time.Second, BlockTime(capped))
+	assert.Equal(t, sdk.NewInt(2), epoch.BlockProvision())
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+
+	montecarloModule "github.com/brianosaurus/challenge2/montecarlo"
+	"github.com/brianosaurus/challenge2/projection"
+	scenarioModule "github.com/brianosaurus/challenge2/scenario"
+)
+
+// WriteBandsCSV writes the percentile bands of a monte carlo run, one row per day
+func WriteBandsCSV(writer *csv.Writer, bands []montecarloModule.Band) {
+	periods := len(bands) > 1 && bands[1].Period != ""
+
+	header := []string{"Days Since Genesis Analyzed", "Date"}
+	if periods {
+		header = append(header, "Period")
+	}
+
+	for _, column := range []string{"Inflation", "Circulating Supply", "Total Supply"} {
+		for _, percentile := range montecarloModule.PERCENTILES {
+			header = append(header, fmt.Sprintf("%s p%g", column, percentile))
+		}
+	}
+
+	err := writer.Write(header)
+	if err != nil {
+		fmt.Println("Error writing to csv")
+		return
+	}
+
+	for i, band := range bands {
+		day := strconv.Itoa(band.Day)
+		if i == 0 {
+			day = projection.START
+		}
+
+		csvStr := []string{day, band.Date.Format("2006-01-02")}
+		if periods {
+			csvStr = append(csvStr, band.Period)
+		}
+
+		for _, inflation := range band.Inflation {
+			csvStr = append(csvStr, strconv.FormatFloat(inflation, 'f', -1, 64))
+		}
+
+		for _, supply := range append(band.CirculatingSupply, band.TotalSupply...) {
+			csvStr = append(csvStr, strconv.FormatFloat(supply, 'f', 0, 64))
+		}
+
+		writer.Write(csvStr)
+	}
+
+	writer.Flush()
+}
+
+// runMonteCarlo projects the genesis many times with random inputs and writes the percentile bands
+func runMonteCarlo(args []string) {
+	flags := flag.NewFlagSet("montecarlo", flag.ExitOnError)
+	csvStr := flags.String("csv", "montecarlo.csv", "the csv file to output the percentile bands to")
+	genesisFile := flags.String("genesis", "genesis.json", "the genesis file to analyze")
+	scenarioFile := flags.String("scenario", "", "a scenario file with the assumptions that are not random (the first scenario is used)")
+	runs := flags.Int("runs", 1000, "the number of projections to run")
+	seed := flags.Int64("seed", 1, "the seed of the random number generator")
+	workers := flags.Int("workers", runtime.NumCPU(), "the number of projections to run in parallel")
+	blockTime := flags.String("block-time", "", "the distribution of the seconds per block, e.g. normal:5,0.5")
+	bondedRatio := flags.String("bonded-ratio", "", "the distribution of the starting bonded ratio, e.g. uniform:0.4,0.7")
+	restakeRatio := flags.String("restake-ratio", "", "the distribution of the share of unvesting tokens that is staked again, e.g. triangular:0,0.3,0.6")
+	flags.Parse(args)
+
+	if *runs <= 0 {
+		fmt.Printf("Invalid number of runs %d, it has to be more than 0\n", *runs)
+		return
+	}
+
+	settings := montecarloModule.Settings{Runs: *runs, Seed: *seed, Workers: *workers}
+
+	var err error
+	for _, distribution := range []struct {
+		target *montecarloModule.Distribution
+		str    string
+	}{{&settings.BlockTime, *blockTime}, {&settings.BondedRatio, *bondedRatio}, {&settings.RestakeRatio, *restakeRatio}} {
+		*distribution.target, err = montecarloModule.ParseDistribution(distribution.str)
+		if err != nil {
+			fmt.Println("Error in distribution:", err)
+			return
+		}
+	}
+
+	scenario := scenarioModule.Scenario{Name: "montecarlo"}
+	if *scenarioFile != "" {
+		scenarios, err := scenarioModule.Load(*scenarioFile)
+		if err != nil {
+			fmt.Println("Error reading scenario file:", err)
+			return
+		}
+
+		scenario = scenarios[0]
+	}
+
+	genesis, err := ReadGenesis(*genesisFile)
+	if err != nil {
+		fmt.Println("Error reading genesis file:", err)
+		return
+	}
+
+	appState := genesis.AppState
+
+	// the vesting schedule is the same for every run, only the model has to be new
+	base, err := scenario.Config(appState)
+	if err != nil {
+		fmt.Println("Error in scenario:", err)
+		return
+	}
+
+	genesis.SetHeights(&base)
+
+	bands := montecarloModule.Run(settings, func() projection.Config {
+		config := base
+
+		// the scenario was already checked when building the base config
+		config.Model, _ = scenario.Model(appState)
+		return config
+	})
+
+	file, err := os.Create(*csvStr)
+	if err != nil {
+		fmt.Println("Error creating csv file")
+		return
+	}
+	defer file.Close()
+
+	WriteBandsCSV(csv.NewWriter(file), bands)
+	fmt.Printf("\nDone\n")
+}
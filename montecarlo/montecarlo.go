@@ -0,0 +1,291 @@
+package montecarlo
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	mintModule "github.com/brianosaurus/challenge2/mint"
+	"github.com/brianosaurus/challenge2/projection"
+)
+
+// the percentiles reported for every day
+var PERCENTILES = []float64{5, 50, 95}
+
+// Distribution is a random input of the simulation. Kind is one of fixed, uniform, normal or
+// triangular and Params are the parameters of that kind of distribution.
+type Distribution struct {
+	Kind   string
+	Params []float64
+}
+
+var distributionParams = map[string]int{
+	"fixed":      1, // value
+	"uniform":    2, // min, max
+	"normal":     2, // mean, standard deviation
+	"triangular": 3, // min, mode, max
+}
+
+// ParseDistribution reads a distribution written as kind:param,param e.g. normal:5,0.5.
+// An empty string is a distribution that is not set.
+func ParseDistribution(str string) (Distribution, error) {
+	if str == "" {
+		return Distribution{}, nil
+	}
+
+	kind, rawParams, _ := strings.Cut(str, ":")
+	count, ok := distributionParams[kind]
+	if !ok {
+		return Distribution{}, fmt.Errorf("unknown distribution %q", kind)
+	}
+
+	params := make([]float64, 0, count)
+	for _, rawParam := range strings.Split(rawParams, ",") {
+		param, err := strconv.ParseFloat(strings.TrimSpace(rawParam), 64)
+		if err != nil {
+			return Distribution{}, fmt.Errorf("invalid parameter in distribution %q: %w", str, err)
+		}
+
+		params = append(params, param)
+	}
+
+	if len(params) != count {
+		return Distribution{}, fmt.Errorf("%s distribution takes %d parameters, got %d", kind, count, len(params))
+	}
+
+	switch {
+	case kind == "uniform" && params[0] > params[1]:
+		return Distribution{}, fmt.Errorf("uniform distribution %q has min more than max", str)
+	case kind == "normal" && params[1] < 0:
+		return Distribution{}, fmt.Errorf("normal distribution %q has a negative standard deviation", str)
+	case kind == "triangular" && params[0] >= params[2]:
+		return Distribution{}, fmt.Errorf("triangular distribution %q needs min less than max", str)
+	case kind == "triangular" && (params[1] < params[0] || params[1] > params[2]):
+		return Distribution{}, fmt.Errorf("triangular distribution %q has its mode outside min and max", str)
+	}
+
+	return Distribution{Kind: kind, Params: params}, nil
+}
+
+// IsSet is false for a distribution parsed from an empty string
+func (d Distribution) IsSet() bool {
+	return d.Kind != ""
+}
+
+// Sample draws a value from the distribution. Values are clamped to min and max so a
+// normal distribution can not go below zero.
+func (d Distribution) Sample(rng *rand.Rand, min float64, max float64) float64 {
+	var value float64
+
+	switch d.Kind {
+	case "fixed":
+		value = d.Params[0]
+	case "uniform":
+		value = d.Params[0] + rng.Float64()*(d.Params[1]-d.Params[0])
+	case "normal":
+		value = d.Params[0] + rng.NormFloat64()*d.Params[1]
+	case "triangular":
+		low, mode, high := d.Params[0], d.Params[1], d.Params[2]
+		u := rng.Float64()
+
+		if u < (mode-low)/(high-low) {
+			value = low + math.Sqrt(u*(high-low)*(mode-low))
+		} else {
+			value = high - math.Sqrt((1-u)*(high-low)*(high-mode))
+		}
+	}
+
+	return math.Min(math.Max(value, min), max)
+}
+
+// Settings are the uncertain inputs of the simulation. Distributions that are not set keep the
+// value from the base projection.
+type Settings struct {
+	// more than zero
+	Runs    int
+	Seed    int64
+	Workers int
+	// seconds per block. x/mint and Juno keep the blocks_per_year of genesis so faster blocks mint
+	// more in a year, epoch models mint the same per epoch whatever the block time
+	BlockTime Distribution
+	// the bonded ratio at the start, after that it moves with the supply and restaking
+	BondedRatio Distribution
+	// the share of unvesting tokens that is staked again
+	RestakeRatio Distribution
+}
+
+// Band is the spread of the projections on one day, every value has one entry per PERCENTILES
+type Band struct {
+	Day  int
+	Date time.Time
+	// the label of the row when the projection is not daily, see projection.Row.Label
+	Period            string
+	Inflation         []float64
+	CirculatingSupply []float64
+	TotalSupply       []float64
+}
+
+type sample struct {
+	blockTime    float64
+	bondedRatio  float64
+	restakeRatio float64
+}
+
+type run struct {
+	inflation   []float64
+	circulating []float64
+	total       []float64
+}
+
+// Run projects the supply settings.Runs times. newConfig is called for every run and has to
+// return a config with a fresh inflation model. All the random inputs are drawn up front from
+// the seed so the result does not depend on how the runs are spread over the workers.
+func Run(settings Settings, newConfig func() projection.Config) []Band {
+	rng := rand.New(rand.NewSource(settings.Seed))
+
+	samples := make([]sample, settings.Runs)
+	for i := range samples {
+		if settings.BlockTime.IsSet() {
+			samples[i].blockTime = settings.BlockTime.Sample(rng, 0.1, math.MaxFloat64)
+		}
+
+		if settings.BondedRatio.IsSet() {
+			samples[i].bondedRatio = settings.BondedRatio.Sample(rng, 0, 1)
+		}
+
+		if settings.RestakeRatio.IsSet() {
+			samples[i].restakeRatio = settings.RestakeRatio.Sample(rng, 0, 1)
+		}
+	}
+
+	workers := settings.Workers
+	if workers <= 0 {
+		workers = 1
+	}
+
+	runs := make([]run, settings.Runs)
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range jobs {
+				runs[i] = project(settings, samples[i], newConfig())
+			}
+		}()
+	}
+
+	for i := range samples {
+		jobs <- i
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	return percentiles(runs, newConfig)
+}
+
+func project(settings Settings, sample sample, config projection.Config) run {
+	if settings.BlockTime.IsSet() {
+		config.BlockTime = time.Duration(sample.blockTime * float64(time.Second))
+		mintModule.SetBlockTime(config.Model, config.BlockTime)
+	}
+
+	if settings.BondedRatio.IsSet() {
+		config.BondedRatio = sdk.Dec{}
+		config.StakedTokens = config.TotalSupply.Mul(floatToDec(sample.bondedRatio))
+	}
+
+	if settings.RestakeRatio.IsSet() {
+		config.RestakeRatio = floatToDec(sample.restakeRatio)
+	}
+
+	projected := projection.Run(config)
+
+	result := run{
+		inflation:   make([]float64, len(projected.Rows)),
+		circulating: make([]float64, len(projected.Rows)),
+		total:       make([]float64, len(projected.Rows)),
+	}
+
+	// floats are plenty precise for percentiles and keep thousands of runs in memory
+	for i, day := range projected.Rows {
+		result.inflation[i] = decToFloat(day.Inflation)
+		result.circulating[i] = decToFloat(day.CirculatingSupply)
+		result.total[i] = decToFloat(day.TotalSupply)
+	}
+
+	return result
+}
+
+func percentiles(runs []run, newConfig func() projection.Config) []Band {
+	if len(runs) == 0 {
+		return nil
+	}
+
+	// the days do not depend on the random inputs so take them from a plain projection
+	plain := projection.Run(newConfig())
+	days := plain.Rows
+	bands := make([]Band, len(days))
+
+	values := make([]float64, len(runs))
+
+	for row := range days {
+		bands[row].Day = days[row].Day
+		bands[row].Date = plain.Date(days[row])
+		bands[row].Period = days[row].Label()
+		bands[row].Inflation = percentilesOf(values, runs, row, func(r run) []float64 { return r.inflation })
+		bands[row].CirculatingSupply = percentilesOf(values, runs, row, func(r run) []float64 { return r.circulating })
+		bands[row].TotalSupply = percentilesOf(values, runs, row, func(r run) []float64 { return r.total })
+	}
+
+	return bands
+}
+
+// nearest rank percentiles of one column of the runs
+func percentilesOf(values []float64, runs []run, row int, column func(run) []float64) []float64 {
+	values = values[:0]
+	for _, r := range runs {
+		if row < len(column(r)) {
+			values = append(values, column(r)[row])
+		}
+	}
+
+	sort.Float64s(values)
+
+	result := make([]float64, len(PERCENTILES))
+	if len(values) == 0 {
+		return result
+	}
+
+	for i, percentile := range PERCENTILES {
+		rank := int(math.Ceil(percentile/100*float64(len(values)))) - 1
+		if rank < 0 {
+			rank = 0
+		}
+
+		result[i] = values[rank]
+	}
+
+	return result
+}
+
+func floatToDec(value float64) sdk.Dec {
+	return sdk.MustNewDecFromStr(strconv.FormatFloat(value, 'f', sdk.Precision, 64))
+}
+
+func decToFloat(value sdk.Dec) float64 {
+	result, _ := strconv.ParseFloat(value.String(), 64)
+	return result
+}
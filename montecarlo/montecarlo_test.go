@@ -0,0 +1,95 @@
+package montecarlo
+
+import (
+	"math/rand"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	mintingTypes "github.com/cosmos/cosmos-sdk/x/mint/types"
+	"github.com/stretchr/testify/assert"
+
+	mintModule "github.com/brianosaurus/challenge2/mint"
+	"github.com/brianosaurus/challenge2/projection"
+)
+
+func TestParseDistribution(t *testing.T) {
+	distribution, err := ParseDistribution("triangular:0, 0.3,0.6")
+	assert.Nil(t, err)
+	assert.Equal(t, "triangular", distribution.Kind)
+	assert.Equal(t, []float64{0, 0.3, 0.6}, distribution.Params)
+
+	distribution, err = ParseDistribution("")
+	assert.Nil(t, err)
+	assert.False(t, distribution.IsSet())
+
+	_, err = ParseDistribution("normal:5")
+	assert.NotNil(t, err)
+
+	_, err = ParseDistribution("poisson:5")
+	assert.NotNil(t, err)
+
+	// the bounds have to be in order
+	for _, str := range []string{"uniform:0.7,0.4", "normal:5,-1", "triangular:5,5,5", "triangular:0,0.7,0.6", "triangular:0.6,0.3,0"} {
+		_, err = ParseDistribution(str)
+		assert.NotNil(t, err, str)
+	}
+
+	distribution, err = ParseDistribution("triangular:0,0,0.6")
+	assert.Nil(t, err)
+	assert.True(t, distribution.Sample(rand.New(rand.NewSource(1)), 0, 1) <= 0.6)
+
+	rng := rand.New(rand.NewSource(1))
+	distribution, _ = ParseDistribution("uniform:0.4,0.7")
+
+	for i := 0; i < 1000; i++ {
+		value := distribution.Sample(rng, 0, 1)
+		assert.True(t, value >= 0.4 && value <= 0.7)
+	}
+
+	// clamped to the bounds
+	distribution, _ = ParseDistribution("fixed:2")
+	assert.Equal(t, float64(1), distribution.Sample(rng, 0, 1))
+}
+
+func TestRun(t *testing.T) {
+	vestingOnDays := map[int]sdk.Dec{0: sdk.NewDec(1000000000), 1: sdk.NewDec(1000000000), 2: sdk.NewDec(1000000000)}
+
+	newConfig := func() projection.Config {
+		params := mintingTypes.DefaultParams()
+		params.BlocksPerYear = mintModule.BLOCKS_PER_YEAR
+
+		return projection.Config{
+			VestingOnDays: &vestingOnDays,
+			TotalSupply:   sdk.NewDec(1000000000000),
+			StakedTokens:  sdk.NewDec(500000000000),
+			Model:         mintModule.NewStandardModel(params, mintingTypes.DefaultInitialMinter()),
+			Fees:          projection.NoFees(),
+		}
+	}
+
+	settings := Settings{Runs: 50, Seed: 42, Workers: 1}
+	settings.BlockTime, _ = ParseDistribution("normal:5,1")
+	settings.BondedRatio, _ = ParseDistribution("uniform:0.2,0.8")
+	settings.RestakeRatio, _ = ParseDistribution("triangular:0,0.3,0.6")
+
+	bands := Run(settings, newConfig)
+	assert.Equal(t, 4, len(bands))
+
+	for _, band := range bands[1:] {
+		assert.True(t, band.Inflation[0] <= band.Inflation[1] && band.Inflation[1] <= band.Inflation[2])
+		assert.True(t, band.TotalSupply[0] <= band.TotalSupply[1] && band.TotalSupply[1] <= band.TotalSupply[2])
+		assert.True(t, band.Inflation[0] < band.Inflation[2])
+	}
+
+	// the same seed gives the same bands no matter how many workers there are
+	settings.Workers = 8
+	assert.Equal(t, bands, Run(settings, newConfig))
+
+	// x/mint keeps blocks_per_year so slower blocks mint less
+	settings = Settings{Runs: 20, Seed: 42, Workers: 1}
+	settings.BlockTime, _ = ParseDistribution("uniform:4,10")
+
+	bands = Run(settings, newConfig)
+	last := bands[len(bands)-1]
+	assert.True(t, last.TotalSupply[0] < last.TotalSupply[2])
+}
@@ -0,0 +1,141 @@
+package parquet
+
+import (
+	"io"
+	"sort"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/xitongsys/parquet-go/types"
+	"github.com/xitongsys/parquet-go/writer"
+
+	"github.com/brianosaurus/challenge2/projection"
+	vestingModule "github.com/brianosaurus/challenge2/vesting"
+)
+
+const (
+	// decimals are stored as 16 byte two's complement, the same as decimal128 in arrow
+	DECIMAL_LENGTH = 16
+
+	// sdk.Dec keeps 18 decimals
+	DEC_SCALE = 18
+)
+
+// ProjectionRecord is one row of the projection. Amounts are whole tokens as decimal(38, 0) and
+// inflation keeps all 18 decimals of sdk.Dec as decimal(38, 18). Start is only true for the first
+// row, the state at the anchor, which has the same day as the end of day 0.
+type ProjectionRecord struct {
+	Start             bool    `parquet:"name=start, type=BOOLEAN"`
+	Day               int64   `parquet:"name=day, type=INT64"`
+	Date              int32   `parquet:"name=date, type=INT32, convertedtype=DATE"`
+	Timestamp         int64   `parquet:"name=timestamp, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	Height            *int64  `parquet:"name=height, type=INT64, repetitiontype=OPTIONAL"`
+	Period            *string `parquet:"name=period, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL"`
+	TokensUnvesting   string  `parquet:"name=tokens_unvesting, type=FIXED_LEN_BYTE_ARRAY, convertedtype=DECIMAL, precision=38, scale=0, length=16"`
+	Inflation         string  `parquet:"name=inflation, type=FIXED_LEN_BYTE_ARRAY, convertedtype=DECIMAL, precision=38, scale=18, length=16"`
+	StakingRewards    string  `parquet:"name=staking_rewards, type=FIXED_LEN_BYTE_ARRAY, convertedtype=DECIMAL, precision=38, scale=0, length=16"`
+	CirculatingSupply string  `parquet:"name=circulating_supply, type=FIXED_LEN_BYTE_ARRAY, convertedtype=DECIMAL, precision=38, scale=0, length=16"`
+	TotalSupply       string  `parquet:"name=total_supply, type=FIXED_LEN_BYTE_ARRAY, convertedtype=DECIMAL, precision=38, scale=0, length=16"`
+	FeesCollected     string  `parquet:"name=fees_collected, type=FIXED_LEN_BYTE_ARRAY, convertedtype=DECIMAL, precision=38, scale=0, length=16"`
+	TokensBurned      string  `parquet:"name=tokens_burned, type=FIXED_LEN_BYTE_ARRAY, convertedtype=DECIMAL, precision=38, scale=0, length=16"`
+	NetSupplyChange   string  `parquet:"name=net_supply_change, type=FIXED_LEN_BYTE_ARRAY, convertedtype=DECIMAL, precision=38, scale=0, length=16"`
+}
+
+// ScheduleRecord is the amount a vesting account unvests on one day counted from the anchor
+type ScheduleRecord struct {
+	Address string `parquet:"name=address, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Type    string `parquet:"name=type, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Denom   string `parquet:"name=denom, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Day     int64  `parquet:"name=day, type=INT64"`
+	Date    int32  `parquet:"name=date, type=INT32, convertedtype=DATE"`
+	Amount  string `parquet:"name=amount, type=FIXED_LEN_BYTE_ARRAY, convertedtype=DECIMAL, precision=38, scale=0, length=16"`
+}
+
+// WriteProjection writes the rows of the projection as parquet
+func WriteProjection(w io.Writer, projected projection.Projection) error {
+	pw, err := writer.NewParquetWriterFromWriter(w, new(ProjectionRecord), 1)
+	if err != nil {
+		return err
+	}
+
+	for i, row := range projected.Rows {
+		record := ProjectionRecord{
+			Start:             i == 0,
+			Day:               int64(row.Day),
+			Date:              date(projected.Date(row)),
+			Timestamp:         row.Time * 1000,
+			TokensUnvesting:   amount(row.TokensUnvesting),
+			Inflation:         decimal(row.Inflation),
+			StakingRewards:    amount(row.StakingRewards),
+			CirculatingSupply: amount(row.CirculatingSupply),
+			TotalSupply:       amount(row.TotalSupply),
+			FeesCollected:     amount(row.FeesCollected),
+			TokensBurned:      amount(row.TokensBurned),
+			NetSupplyChange:   amount(row.NetSupplyChange),
+		}
+
+		if row.Height != 0 {
+			height := row.Height
+			record.Height = &height
+		}
+
+		if label := row.Label(); label != "" {
+			record.Period = &label
+		}
+
+		if err = pw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	return pw.WriteStop()
+}
+
+// WriteSchedules writes the daily unvesting of every account as parquet, one record per account and day
+func WriteSchedules(w io.Writer, accounts []vestingModule.Account, anchor int64) error {
+	pw, err := writer.NewParquetWriterFromWriter(w, new(ScheduleRecord), 1)
+	if err != nil {
+		return err
+	}
+
+	for _, account := range accounts {
+		schedule := account.ScheduleAt(anchor)
+
+		days := []int{}
+		for day := range schedule {
+			days = append(days, day)
+		}
+		sort.Ints(days)
+
+		for _, day := range days {
+			err = pw.Write(ScheduleRecord{
+				Address: account.Address,
+				Type:    account.Type,
+				Denom:   account.Denom,
+				Day:     int64(day),
+				Date:    date(time.Unix(anchor+int64(day)*86400, 0)),
+				Amount:  amount(schedule[day]),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return pw.WriteStop()
+}
+
+// date is the number of days since 1970-01-01, the parquet DATE type
+func date(t time.Time) int32 {
+	return int32(t.UTC().Unix() / 86400)
+}
+
+// amount is a whole amount of tokens as a decimal with scale 0
+func amount(value sdk.Dec) string {
+	return types.StrIntToBinary(value.RoundInt().String(), "BigEndian", DECIMAL_LENGTH, true)
+}
+
+// decimal keeps every decimal of the sdk.Dec, its big int is already scaled by 10^18
+func decimal(value sdk.Dec) string {
+	return types.StrIntToBinary(value.BigInt().String(), "BigEndian", DECIMAL_LENGTH, true)
+}
@@ -0,0 +1,113 @@
+package parquet
+
+import (
+	"bytes"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	mintingTypes "github.com/cosmos/cosmos-sdk/x/mint/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/xitongsys/parquet-go-source/buffer"
+	parquetTypes "github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/reader"
+	"github.com/xitongsys/parquet-go/types"
+
+	mintModule "github.com/brianosaurus/challenge2/mint"
+	"github.com/brianosaurus/challenge2/projection"
+	vestingModule "github.com/brianosaurus/challenge2/vesting"
+)
+
+func TestWriteProjection(t *testing.T) {
+	vestingOnDays := map[int]sdk.Dec{0: sdk.NewDec(1000), 2: sdk.NewDec(2000)}
+	projected := projection.Run(projection.Config{
+		VestingOnDays: &vestingOnDays,
+		// more than fits in an int64
+		TotalSupply:   sdk.NewDec(10).Power(25),
+		StakedTokens:  sdk.NewDec(10).Power(24),
+		Model:         mintModule.NewStandardModel(mintingTypes.DefaultParams(), mintingTypes.DefaultInitialMinter()),
+		Fees:          projection.NoFees(),
+		Anchor:        1669075200, // 2022-11-22
+		GenesisTime:   1669075200,
+		InitialHeight: 1,
+	})
+
+	var buf bytes.Buffer
+	assert.Nil(t, WriteProjection(&buf, projected))
+
+	file, err := buffer.NewBufferFile(buf.Bytes())
+	assert.Nil(t, err)
+
+	pr, err := reader.NewParquetReader(file, new(ProjectionRecord), 1)
+	assert.Nil(t, err)
+	defer pr.ReadStop()
+
+	assert.Equal(t, int64(len(projected.Rows)), pr.GetNumRows())
+
+	// the columns keep their types for pandas and duckdb
+	convertedTypes := map[string]parquetTypes.ConvertedType{}
+	for _, element := range pr.Footer.Schema {
+		if element.ConvertedType != nil {
+			convertedTypes[element.Name] = *element.ConvertedType
+		}
+	}
+
+	assert.Equal(t, parquetTypes.ConvertedType_DATE, convertedTypes["Date"])
+	assert.Equal(t, parquetTypes.ConvertedType_TIMESTAMP_MILLIS, convertedTypes["Timestamp"])
+	assert.Equal(t, parquetTypes.ConvertedType_DECIMAL, convertedTypes["Inflation"])
+	assert.Equal(t, parquetTypes.ConvertedType_DECIMAL, convertedTypes["TotalSupply"])
+
+	records := make([]ProjectionRecord, pr.GetNumRows())
+	assert.Nil(t, pr.Read(&records))
+
+	first := records[0]
+	assert.True(t, first.Start)
+	assert.False(t, records[1].Start)
+	assert.Equal(t, first.Day, records[1].Day)
+	assert.Equal(t, int32(19318), first.Date)
+	assert.Equal(t, int64(1669075200000), first.Timestamp)
+	assert.Nil(t, first.Period)
+	assert.Equal(t, int64(1), *first.Height)
+	assert.Equal(t, sdk.NewDecWithPrec(13, 2), inflation(first))
+	assert.Equal(t, "10000000000000000000000000", types.DECIMAL_BYTE_ARRAY_ToString([]byte(first.TotalSupply), 38, 0))
+
+	last := records[len(records)-1]
+	assert.Equal(t, projected.Rows[len(records)-1].Inflation, inflation(last))
+	assert.Equal(t, "2000", types.DECIMAL_BYTE_ARRAY_ToString([]byte(last.TokensUnvesting), 38, 0))
+}
+
+func TestWriteSchedules(t *testing.T) {
+	anchor := int64(1669075200)
+	accounts := []vestingModule.Account{
+		{Address: "umee1delayed", Type: vestingModule.ACCOUNT_DELAYED, Denom: "uumee", OriginalVesting: sdk.NewInt(5000), EndTime: anchor + 3*86400},
+		{Address: "umee1continuous", Type: vestingModule.ACCOUNT_CONTINUOUS, Denom: "uumee", OriginalVesting: sdk.NewInt(17280 * 4),
+			StartTime: anchor - 2*86400, EndTime: anchor + 2*86400},
+	}
+
+	var buf bytes.Buffer
+	assert.Nil(t, WriteSchedules(&buf, accounts, anchor))
+
+	file, err := buffer.NewBufferFile(buf.Bytes())
+	assert.Nil(t, err)
+
+	pr, err := reader.NewParquetReader(file, new(ScheduleRecord), 1)
+	assert.Nil(t, err)
+	defer pr.ReadStop()
+
+	records := make([]ScheduleRecord, pr.GetNumRows())
+	assert.Nil(t, pr.Read(&records))
+
+	assert.Equal(t, 3, len(records))
+	assert.Equal(t, "umee1delayed", records[0].Address)
+	assert.Equal(t, int32(19321), records[0].Date)
+	assert.Equal(t, "5000", types.DECIMAL_BYTE_ARRAY_ToString([]byte(records[0].Amount), 38, 0))
+
+	assert.Equal(t, "umee1continuous", records[1].Address)
+	assert.Equal(t, int64(0), records[1].Day)
+	assert.Equal(t, int64(1), records[2].Day)
+	assert.Equal(t, "17280", types.DECIMAL_BYTE_ARRAY_ToString([]byte(records[2].Amount), 38, 0))
+}
+
+// inflation reads the decimal back, the reader writes it without the leading zero
+func inflation(record ProjectionRecord) sdk.Dec {
+	return sdk.MustNewDecFromStr("0" + types.DECIMAL_BYTE_ARRAY_ToString([]byte(record.Inflation), 38, 18))
+}
@@ -0,0 +1,96 @@
+package projection
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Metric is a value of a row two projections are compared on
+type Metric struct {
+	Name  string
+	Value func(Row) sdk.Dec
+	// ratios are written as is, amounts as whole tokens
+	Ratio bool
+}
+
+var METRICS = []Metric{
+	{Name: "Inflation", Value: func(r Row) sdk.Dec { return r.Inflation }, Ratio: true},
+	{Name: "Staking Rewards", Value: func(r Row) sdk.Dec { return r.StakingRewards }},
+	{Name: "Circulating Supply", Value: func(r Row) sdk.Dec { return r.CirculatingSupply }},
+	{Name: "Total Supply", Value: func(r Row) sdk.Dec { return r.TotalSupply }},
+	{Name: "Tokens Unvesting", Value: func(r Row) sdk.Dec { return r.TokensUnvesting }},
+}
+
+// Stats summarizes how a metric of B differs from A over the rows both projections have.
+// MaxDelta is the delta furthest from zero and MaxDeltaDay the day of its row.
+type Stats struct {
+	Metric      Metric
+	FinalA      sdk.Dec
+	FinalB      sdk.Dec
+	FinalDelta  sdk.Dec
+	MeanDelta   sdk.Dec
+	MaxDelta    sdk.Dec
+	MaxDeltaDay int
+}
+
+// RelativeDelta is the final delta relative to the final value of A, zero when A is zero
+func (s Stats) RelativeDelta() sdk.Dec {
+	if s.FinalA.IsZero() {
+		return sdk.ZeroDec()
+	}
+
+	return s.FinalDelta.Quo(s.FinalA)
+}
+
+// Comparison overlays two projections. Rows line up by their position, so both should have the same
+// granularity and anchor, and only the rows both have are compared.
+type Comparison struct {
+	A     Projection
+	B     Projection
+	Rows  int
+	Stats []Stats
+}
+
+// Delta is B - A of a metric in a row
+func (c Comparison) Delta(metric Metric, row int) sdk.Dec {
+	return metric.Value(c.B.Rows[row]).Sub(metric.Value(c.A.Rows[row]))
+}
+
+// Compare computes the deltas of every metric between two projections and summarizes them
+func Compare(a Projection, b Projection) Comparison {
+	comparison := Comparison{A: a, B: b, Rows: len(a.Rows)}
+	if len(b.Rows) < comparison.Rows {
+		comparison.Rows = len(b.Rows)
+	}
+
+	if comparison.Rows == 0 {
+		return comparison
+	}
+
+	last := comparison.Rows - 1
+
+	for _, metric := range METRICS {
+		stats := Stats{
+			Metric:   metric,
+			FinalA:   metric.Value(a.Rows[last]),
+			FinalB:   metric.Value(b.Rows[last]),
+			MaxDelta: sdk.ZeroDec(),
+		}
+		stats.FinalDelta = stats.FinalB.Sub(stats.FinalA)
+
+		sum := sdk.ZeroDec()
+		for row := 0; row < comparison.Rows; row++ {
+			delta := comparison.Delta(metric, row)
+			sum = sum.Add(delta)
+
+			if delta.Abs().GT(stats.MaxDelta.Abs()) {
+				stats.MaxDelta = delta
+				stats.MaxDeltaDay = a.Rows[row].Day
+			}
+		}
+
+		stats.MeanDelta = sum.QuoInt64(int64(comparison.Rows))
+		comparison.Stats = append(comparison.Stats, stats)
+	}
+
+	return comparison
+}
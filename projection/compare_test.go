@@ -0,0 +1,87 @@
+package projection
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	mintingTypes "github.com/cosmos/cosmos-sdk/x/mint/types"
+	"github.com/stretchr/testify/assert"
+
+	mintModule "github.com/brianosaurus/challenge2/mint"
+)
+
+func supplyRows(supplies ...int64) []Row {
+	rows := []Row{}
+
+	for day, supply := range supplies {
+		rows = append(rows, Row{
+			Day:               day - 1,
+			Inflation:         sdk.NewDecWithPrec(7, 2),
+			StakingRewards:    sdk.ZeroDec(),
+			CirculatingSupply: sdk.NewDec(supply),
+			TotalSupply:       sdk.NewDec(supply),
+			TokensUnvesting:   sdk.ZeroDec(),
+		})
+	}
+
+	return rows
+}
+
+func TestCompare(t *testing.T) {
+	a := Projection{Rows: supplyRows(1000, 1100, 1200, 1300)}
+	b := Projection{Rows: supplyRows(1000, 1150, 1100)}
+
+	comparison := Compare(a, b)
+
+	// only the rows both have
+	assert.Equal(t, 3, comparison.Rows)
+	assert.Len(t, comparison.Stats, len(METRICS))
+
+	stats := comparison.Stats[3]
+	assert.Equal(t, "Total Supply", stats.Metric.Name)
+	assert.Equal(t, sdk.NewDec(1200), stats.FinalA)
+	assert.Equal(t, sdk.NewDec(1100), stats.FinalB)
+	assert.Equal(t, sdk.NewDec(-100), stats.FinalDelta)
+	assert.Equal(t, sdk.NewDec(-100), stats.MaxDelta)
+	assert.Equal(t, 1, stats.MaxDeltaDay)
+	assert.Equal(t, sdk.NewDec(-50).QuoInt64(3), stats.MeanDelta)
+	assert.Equal(t, sdk.NewDec(-1).QuoInt64(12), stats.RelativeDelta())
+
+	assert.Equal(t, sdk.NewDec(50), comparison.Delta(stats.Metric, 1))
+
+	// inflation is the same every day
+	assert.True(t, comparison.Stats[0].FinalDelta.IsZero())
+	assert.True(t, comparison.Stats[0].MaxDelta.IsZero())
+	assert.Equal(t, 0, comparison.Stats[0].MaxDeltaDay)
+}
+
+func TestCompareRuns(t *testing.T) {
+	params := mintingTypes.DefaultParams()
+	params.BlocksPerYear = mintModule.BLOCKS_PER_YEAR
+
+	vestingOnDays := map[int]sdk.Dec{0: sdk.NewDec(1000), 9: sdk.NewDec(1000)}
+
+	run := func(burnRatio string) Projection {
+		fees, err := NewFees("1000", burnRatio, "0", "0")
+		assert.Nil(t, err)
+
+		return Run(Config{
+			VestingOnDays: &vestingOnDays,
+			TotalSupply:   sdk.NewDec(1000000000000),
+			StakedTokens:  sdk.ZeroDec(),
+			BondedRatio:   params.GoalBonded,
+			Model:         mintModule.NewStandardModel(params, mintingTypes.DefaultInitialMinter()),
+			Fees:          fees,
+		})
+	}
+
+	comparison := Compare(run("0"), run("0.5"))
+	assert.Equal(t, 11, comparison.Rows)
+
+	// burning half of the fees takes 500 tokens out of the supply every day
+	stats := comparison.Stats[3]
+	assert.Equal(t, sdk.NewDec(-5000), stats.FinalDelta)
+	assert.Equal(t, sdk.NewDec(-5000), stats.MaxDelta)
+	assert.Equal(t, 9, stats.MaxDeltaDay)
+	assert.True(t, comparison.Delta(stats.Metric, 0).IsZero())
+}
@@ -0,0 +1,83 @@
+package projection
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Fees describes the transaction fees paid every day and where they go. The share of the fees
+// that is neither burned nor given to stakers goes to the community pool and stays in circulation.
+// DailyBuyback is burned every day on top of the burned fees (buy back and burn programs).
+type Fees struct {
+	DailyVolume  sdk.Dec
+	BurnRatio    sdk.Dec
+	StakerShare  sdk.Dec
+	DailyBuyback sdk.Dec
+}
+
+// NoFees is used when the simulation should not model fees at all
+func NoFees() Fees {
+	return Fees{
+		DailyVolume:  sdk.ZeroDec(),
+		BurnRatio:    sdk.ZeroDec(),
+		StakerShare:  sdk.ZeroDec(),
+		DailyBuyback: sdk.ZeroDec(),
+	}
+}
+
+func NewFees(dailyVolume string, burnRatio string, stakerShare string, dailyBuyback string) (Fees, error) {
+	fees := NoFees()
+	var err error
+
+	if fees.DailyVolume, err = sdk.NewDecFromStr(dailyVolume); err != nil {
+		return fees, fmt.Errorf("invalid daily fee volume: %w", err)
+	}
+
+	if fees.BurnRatio, err = sdk.NewDecFromStr(burnRatio); err != nil {
+		return fees, fmt.Errorf("invalid burn ratio: %w", err)
+	}
+
+	if fees.StakerShare, err = sdk.NewDecFromStr(stakerShare); err != nil {
+		return fees, fmt.Errorf("invalid staker share: %w", err)
+	}
+
+	if fees.DailyBuyback, err = sdk.NewDecFromStr(dailyBuyback); err != nil {
+		return fees, fmt.Errorf("invalid daily buyback: %w", err)
+	}
+
+	// negative amounts or ratios would mint fees, ratios above one burn or pay out more than was collected
+	for _, amount := range []struct {
+		name  string
+		value sdk.Dec
+	}{{"daily fee volume", fees.DailyVolume}, {"daily buyback", fees.DailyBuyback}} {
+		if amount.value.IsNegative() {
+			return fees, fmt.Errorf("%s %s is negative", amount.name, amount.value)
+		}
+	}
+
+	for _, ratio := range []struct {
+		name  string
+		value sdk.Dec
+	}{{"burn ratio", fees.BurnRatio}, {"staker share", fees.StakerShare}} {
+		if ratio.value.IsNegative() || ratio.value.GT(sdk.OneDec()) {
+			return fees, fmt.Errorf("%s %s is not between 0 and 1", ratio.name, ratio.value)
+		}
+	}
+
+	if fees.BurnRatio.Add(fees.StakerShare).GT(sdk.OneDec()) {
+		return fees, fmt.Errorf("burn ratio plus staker share is more than one")
+	}
+
+	return fees, nil
+}
+
+// Burned is the amount of tokens that are destroyed every day
+func (f Fees) Burned() sdk.Dec {
+	return f.DailyVolume.Mul(f.BurnRatio).Add(f.DailyBuyback)
+}
+
+// ToStakers is the amount of fees that are paid out to stakers every day
+func (f Fees) ToStakers() sdk.Dec {
+	return f.DailyVolume.Mul(f.StakerShare)
+}
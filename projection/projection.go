@@ -0,0 +1,449 @@
+package projection
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	mintModule "github.com/brianosaurus/challenge2/mint"
+)
+
+const (
+	// the day of the first row in csvs, it is the state at the anchor before the first day has run
+	START = "start"
+
+	GRANULARITY_BLOCK   = "block"
+	GRANULARITY_HOUR    = "hour"
+	GRANULARITY_DAY     = "day"
+	GRANULARITY_WEEK    = "week"
+	GRANULARITY_MONTH   = "month"
+	GRANULARITY_QUARTER = "quarter"
+)
+
+var GRANULARITIES = []string{GRANULARITY_BLOCK, GRANULARITY_HOUR, GRANULARITY_DAY, GRANULARITY_WEEK, GRANULARITY_MONTH, GRANULARITY_QUARTER}
+
+// Config is everything the simulation needs to project the supply forward
+type Config struct {
+	VestingOnDays *map[int]sdk.Dec
+	TotalSupply   sdk.Dec
+	StakedTokens  sdk.Dec
+	// BondedRatio replaces StakedTokens / TotalSupply when it is set
+	BondedRatio sdk.Dec
+	// the share of the tokens unvesting every day that is staked right away
+	RestakeRatio sdk.Dec
+	Model        mintModule.InflationModel
+	Fees         Fees
+	// how long blocks really take, defaults to the blocks_per_year of the model
+	BlockTime time.Duration
+	// the number of days to project, defaults to the day after the last unlock
+	Days int
+	// one of GRANULARITIES, defaults to a row per day
+	Granularity string
+	// unix time of the start of day 0, weeks, months and quarters follow the calendar from here
+	Anchor int64
+	// when GenesisTime (unix) is set the block height of every row is estimated from it
+	GenesisTime   int64
+	InitialHeight int64
+	// the staked tokens of vesting accounts that have not vested at the anchor and how much of them vests on each day
+	DelegatedVesting       sdk.Dec
+	DelegatedVestingOnDays *map[int]sdk.Dec
+	// the part of VestingOnDays of each cohort, every row gets a CohortUnvesting when it is set
+	CohortVesting map[string]map[int]sdk.Dec
+}
+
+// Row is one row of the projection. Day is the day the row ends on. Hour and Block are only set when
+// the granularity is finer than a day and Period names the week, month or quarter of the row.
+// Time is the unix time at the end of the row and Height the estimated block height at that time.
+// StakingRewards is the total since the start of the projection, TokensUnvesting, FeesCollected,
+// TokensBurned and NetSupplyChange are for the period of the row only. CohortUnvesting splits
+// TokensUnvesting by cohort.
+//
+// Liquid, StakedVested, StakedUnvested and Locked split the total supply so nothing is counted twice:
+// StakedUnvested is staked by vesting accounts and still vesting, Locked is vesting and not staked,
+// StakedVested is the rest of the staked tokens and Liquid is everything else.
+type Row struct {
+	Day               int
+	Hour              int
+	Block             int
+	Period            string
+	Time              int64
+	Height            int64
+	TokensUnvesting   sdk.Dec
+	Inflation         sdk.Dec
+	StakingRewards    sdk.Dec
+	CirculatingSupply sdk.Dec
+	TotalSupply       sdk.Dec
+	FeesCollected     sdk.Dec
+	TokensBurned      sdk.Dec
+	NetSupplyChange   sdk.Dec
+	Liquid            sdk.Dec
+	StakedVested      sdk.Dec
+	StakedUnvested    sdk.Dec
+	Locked            sdk.Dec
+	CohortUnvesting   map[string]sdk.Dec
+}
+
+// Projection is the result of Run. CapReachedOn is the day the max supply was reached or -1
+// if the model is not capped or the cap was never reached.
+type Projection struct {
+	Rows         []Row
+	Granularity  string
+	Anchor       int64
+	CapReachedOn int
+}
+
+// Date is the UTC day a row is for
+func (p Projection) Date(row Row) time.Time {
+	return time.Unix(p.Anchor, 0).UTC().AddDate(0, 0, row.Day)
+}
+
+// DayColumn is the day of the row at index as written in csvs. The first row is START, it has the same day
+// and date as the end of the first day but is a whole day before it.
+func (p Projection) DayColumn(index int) string {
+	if index == 0 {
+		return START
+	}
+
+	return strconv.Itoa(p.Rows[index].Day)
+}
+
+// ValidGranularity returns an error for granularities Run does not know
+func ValidGranularity(granularity string) error {
+	if granularity == "" {
+		return nil
+	}
+
+	for _, known := range GRANULARITIES {
+		if granularity == known {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown granularity %q", granularity)
+}
+
+// Run simulates the supply day by day. The first row is the state before anything has happened,
+// after that there is a row for every period of the horizon whether tokens unvest in it or not.
+//
+// Inflation is recalculated every hour and rewards are minted for every block of the hour, one every BlockTime
+// around the clock. Unlocks and fees are settled at the end of the day, so with hour or block granularity they
+// show up in the last row of the day (hour 24).
+func Run(config Config) Projection {
+	vestingOnDays := *config.VestingOnDays
+	totalSupply := config.TotalSupply
+	model := config.Model
+
+	stakedTokens := config.StakedTokens
+
+	blockTime := config.BlockTime
+	if blockTime == 0 {
+		blockTime = mintModule.BlockTime(model)
+	}
+
+	granularity := config.Granularity
+	if granularity == "" {
+		granularity = GRANULARITY_DAY
+	}
+
+	intraDay := granularity == GRANULARITY_BLOCK || granularity == GRANULARITY_HOUR
+
+	projection := Projection{Granularity: granularity, Anchor: config.Anchor, CapReachedOn: -1}
+	capped, isCapped := model.(*mintModule.CappedModel)
+
+	horizon := config.Days
+	if horizon <= 0 {
+		horizon = LastUnlock(vestingOnDays) + 1
+	}
+
+	// is total in circulation really correct here? Vesting tokens are unaccessible however they came from an
+	// account initially. So they have been minted already. In any case, I'll go with the assumption that
+	// tokens that haven't yet been vested are not in circulation however staked tokens are in circulation
+	// because staked tokens can be retrived even if there is a lockout period. Excluding staked yet to vest tokens.
+	totalInCirculation := totalSupply
+
+	for _, unvesting := range vestingOnDays {
+		totalInCirculation = totalInCirculation.Sub(unvesting)
+	}
+
+	stakingRewards := sdk.NewDec(0)
+
+	stakedUnvested := sdk.NewDec(0)
+	if !config.DelegatedVesting.IsNil() {
+		stakedUnvested = config.DelegatedVesting
+	}
+
+	delegatedVestingOnDays := map[int]sdk.Dec{}
+	if config.DelegatedVestingOnDays != nil {
+		delegatedVestingOnDays = *config.DelegatedVestingOnDays
+	}
+
+	// what each cohort unvests on a day, rows other than the end of a day pass -1 to get zeros
+	cohortUnvesting := func(day int) map[string]sdk.Dec {
+		if config.CohortVesting == nil {
+			return nil
+		}
+
+		unvesting := make(map[string]sdk.Dec, len(config.CohortVesting))
+		for cohort, vesting := range config.CohortVesting {
+			unvesting[cohort] = sdk.NewDec(0)
+			if amount, ok := vesting[day]; ok {
+				unvesting[cohort] = amount
+			}
+		}
+
+		return unvesting
+	}
+
+	// the state right now with what changed since supplyBefore, elapsed is the time since the anchor
+	row := func(day int, hour int, block int, elapsed time.Duration, unvesting sdk.Dec, fees sdk.Dec, burned sdk.Dec, supplyBefore sdk.Dec) Row {
+		staked := stakedTokens
+		if !config.BondedRatio.IsNil() {
+			staked = totalSupply.Mul(config.BondedRatio)
+		}
+
+		stakedVested := staked.Sub(stakedUnvested)
+		if stakedVested.IsNegative() {
+			stakedVested = sdk.NewDec(0)
+		}
+
+		locked := totalSupply.Sub(totalInCirculation).Sub(stakedUnvested)
+
+		return Row{
+			Day:               day,
+			Hour:              hour,
+			Block:             block,
+			Time:              config.Anchor + int64(elapsed/time.Second),
+			TokensUnvesting:   unvesting,
+			Inflation:         model.Inflation(),
+			StakingRewards:    stakingRewards,
+			CirculatingSupply: totalInCirculation,
+			TotalSupply:       totalSupply,
+			FeesCollected:     fees,
+			TokensBurned:      burned,
+			NetSupplyChange:   totalSupply.Sub(supplyBefore),
+			Liquid:            totalInCirculation.Sub(stakedVested),
+			StakedVested:      stakedVested,
+			StakedUnvested:    stakedUnvested,
+			Locked:            locked,
+			CohortUnvesting:   cohortUnvesting(-1),
+		}
+	}
+
+	projection.Rows = append(projection.Rows, row(0, 0, 0, 0, sdk.NewDec(0), sdk.NewDec(0), sdk.NewDec(0), totalSupply))
+
+	// the time of the last block since the anchor, blocks keep their pace across hours and days
+	lastBlock := time.Duration(0)
+
+	for day := 0; day < horizon; day++ {
+		unvesting, ok := vestingOnDays[day]
+		if !ok {
+			unvesting = sdk.NewDec(0)
+		}
+
+		// calculate inflation for the previous day (rewards are calculated and rewarded every block)
+		stakingRatio := config.BondedRatio
+		if stakingRatio.IsNil() {
+			stakingRatio = stakedTokens.Quo(totalSupply)
+		}
+
+		supplyAtStartOfDay := totalSupply
+		supplyAtStartOfLastHour := totalSupply
+
+		dayStart := time.Duration(day) * time.Hour * 24
+
+		// calculate rewards for each hour of the day
+		for hour := 1; hour <= 24; hour++ {
+			supplyAtStartOfHour := totalSupply
+			supplyAtStartOfLastHour = totalSupply
+			hourEnd := dayStart + time.Duration(hour)*time.Hour
+
+			// inflation changes hourly so make these calculations hourly
+			model.Step(time.Hour, stakingRatio, totalSupply.RoundInt())
+
+			// rewards are distributed every block, a block that does not fit in the hour is minted in the next one
+			blocks := int64((hourEnd - lastBlock) / blockTime)
+
+			if granularity == GRANULARITY_BLOCK {
+				for block := 1; block <= int(blocks); block++ {
+					supplyAtStartOfBlock := totalSupply
+					lastBlock += blockTime
+
+					provision := sdk.NewDecFromInt(model.BlockProvision())
+					stakingRewards = stakingRewards.Add(provision)
+					totalInCirculation = totalInCirculation.Add(provision)
+					totalSupply = totalSupply.Add(provision)
+
+					projection.Rows = append(projection.Rows, row(day, hour, block, lastBlock, sdk.NewDec(0), sdk.NewDec(0), sdk.NewDec(0), supplyAtStartOfBlock))
+				}
+			} else {
+				lastBlock += time.Duration(blocks) * blockTime
+
+				provision := sdk.NewDecFromInt(mintModule.Provisions(model, blocks))
+				stakingRewards = stakingRewards.Add(provision)
+				totalInCirculation = totalInCirculation.Add(provision)
+				totalSupply = totalSupply.Add(provision)
+			}
+
+			// the last hour ends with the unlocks and fees of the day below
+			if granularity == GRANULARITY_HOUR && hour < 24 {
+				projection.Rows = append(projection.Rows, row(day, hour, 0, hourEnd, sdk.NewDec(0), sdk.NewDec(0), sdk.NewDec(0), supplyAtStartOfHour))
+			}
+		}
+
+		supplyAtEndOfDay := totalSupply
+
+		// fees move between accounts so only the burned part changes the supply. Stakers earn their share on top of inflation
+		burned := config.Fees.Burned()
+		stakingRewards = stakingRewards.Add(config.Fees.ToStakers())
+		totalInCirculation = totalInCirculation.Sub(burned)
+		totalSupply = totalSupply.Sub(burned)
+
+		totalInCirculation = totalInCirculation.Add(unvesting) // add recently unvested tokens to total in circulation
+
+		// staked tokens that vest stay staked
+		if delegatedVesting, ok := delegatedVestingOnDays[day]; ok {
+			stakedUnvested = stakedUnvested.Sub(delegatedVesting)
+		}
+
+		if !config.RestakeRatio.IsNil() {
+			stakedTokens = stakedTokens.Add(unvesting.Mul(config.RestakeRatio))
+		}
+
+		dayEnd := dayStart + time.Hour*24
+		if granularity == GRANULARITY_HOUR {
+			projection.Rows = append(projection.Rows, row(day, 24, 0, dayEnd, unvesting, config.Fees.DailyVolume, burned, supplyAtStartOfLastHour))
+		} else if intraDay {
+			projection.Rows = append(projection.Rows, row(day, 24, 0, dayEnd, unvesting, config.Fees.DailyVolume, burned, supplyAtEndOfDay))
+		} else {
+			projection.Rows = append(projection.Rows, row(day, 0, 0, dayEnd, unvesting, config.Fees.DailyVolume, burned, supplyAtStartOfDay))
+		}
+
+		projection.Rows[len(projection.Rows)-1].CohortUnvesting = cohortUnvesting(day)
+
+		if isCapped && projection.CapReachedOn < 0 && capped.Reached() {
+			projection.CapReachedOn = day
+		}
+	}
+
+	if config.GenesisTime != 0 {
+		for i := range projection.Rows {
+			projection.Rows[i].Height = EstimateHeight(projection.Rows[i].Time, config.GenesisTime, config.InitialHeight, blockTime)
+		}
+	}
+
+	if !intraDay && granularity != GRANULARITY_DAY {
+		projection.Rows = Aggregate(projection.Rows, granularity, config.Anchor)
+	}
+
+	return projection
+}
+
+// Aggregate rolls daily rows up into weeks, months or quarters of the calendar starting at anchor.
+// Amounts that are per period are summed, everything else is taken from the last day of the period.
+// The first row is the starting state and is kept as is.
+func Aggregate(rows []Row, granularity string, anchor int64) []Row {
+	if len(rows) == 0 {
+		return rows
+	}
+
+	aggregated := []Row{rows[0]}
+
+	for _, day := range rows[1:] {
+		period := PeriodOf(day.Day, granularity, anchor)
+		last := &aggregated[len(aggregated)-1]
+
+		if len(aggregated) == 1 || last.Period != period {
+			day.Period = period
+
+			// the sums below must not change the daily row
+			if day.CohortUnvesting != nil {
+				cohortUnvesting := make(map[string]sdk.Dec, len(day.CohortUnvesting))
+				for cohort, unvesting := range day.CohortUnvesting {
+					cohortUnvesting[cohort] = unvesting
+				}
+				day.CohortUnvesting = cohortUnvesting
+			}
+
+			aggregated = append(aggregated, day)
+			continue
+		}
+
+		last.Day = day.Day
+		last.TokensUnvesting = last.TokensUnvesting.Add(day.TokensUnvesting)
+		last.Inflation = day.Inflation
+		last.StakingRewards = day.StakingRewards
+		last.CirculatingSupply = day.CirculatingSupply
+		last.TotalSupply = day.TotalSupply
+		last.FeesCollected = last.FeesCollected.Add(day.FeesCollected)
+		last.TokensBurned = last.TokensBurned.Add(day.TokensBurned)
+		last.NetSupplyChange = last.NetSupplyChange.Add(day.NetSupplyChange)
+		last.Liquid = day.Liquid
+		last.StakedVested = day.StakedVested
+		last.StakedUnvested = day.StakedUnvested
+		last.Locked = day.Locked
+
+		for cohort, unvesting := range day.CohortUnvesting {
+			last.CohortUnvesting[cohort] = last.CohortUnvesting[cohort].Add(unvesting)
+		}
+	}
+
+	return aggregated
+}
+
+// PeriodOf names the calendar week (2023-W07), month (2023-02) or quarter (2023-Q1) a day falls in
+func PeriodOf(day int, granularity string, anchor int64) string {
+	date := time.Unix(anchor, 0).UTC().AddDate(0, 0, day)
+
+	switch granularity {
+	case GRANULARITY_WEEK:
+		year, week := date.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	case GRANULARITY_MONTH:
+		return date.Format("2006-01")
+	case GRANULARITY_QUARTER:
+		return fmt.Sprintf("%d-Q%d", date.Year(), (int(date.Month())-1)/3+1)
+	}
+
+	return date.Format("2006-01-02")
+}
+
+// Label names the period of a row finer or coarser than a day, e.g. "hour 3 block 12" or "2023-Q1"
+func (r Row) Label() string {
+	if r.Period != "" {
+		return r.Period
+	}
+
+	if r.Block > 0 {
+		return fmt.Sprintf("hour %d block %d", r.Hour, r.Block)
+	}
+
+	if r.Hour > 0 {
+		return fmt.Sprintf("hour %d", r.Hour)
+	}
+
+	return ""
+}
+
+// EstimateHeight is the height of the chain at unix time when blocks are blockTime apart since genesis
+func EstimateHeight(when int64, genesisTime int64, initialHeight int64, blockTime time.Duration) int64 {
+	if when < genesisTime {
+		return 0
+	}
+
+	return initialHeight + int64(time.Duration(when-genesisTime)*time.Second/blockTime)
+}
+
+// LastUnlock is the last day tokens unvest on, -1 when nothing unvests
+func LastUnlock(vestingOnDays map[int]sdk.Dec) int {
+	last := -1
+
+	for day := range vestingOnDays {
+		if day > last {
+			last = day
+		}
+	}
+
+	return last
+}
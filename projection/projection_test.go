@@ -0,0 +1,281 @@
+package projection
+
+import (
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	mintingTypes "github.com/cosmos/cosmos-sdk/x/mint/types"
+	"github.com/stretchr/testify/assert"
+
+	mintModule "github.com/brianosaurus/challenge2/mint"
+)
+
+func TestNewFees(t *testing.T) {
+	fees, err := NewFees("1000", "0.5", "0.25", "100")
+	assert.Nil(t, err)
+	assert.Equal(t, sdk.NewDec(600), fees.Burned())
+	assert.Equal(t, sdk.NewDec(250), fees.ToStakers())
+
+	_, err = NewFees("1000", "0.75", "0.5", "0")
+	assert.NotNil(t, err)
+
+	_, err = NewFees("lots", "0", "0", "0")
+	assert.NotNil(t, err)
+
+	// nothing can mint fees or burn more than was collected
+	_, err = NewFees("-1000", "0.5", "0", "0")
+	assert.EqualError(t, err, "daily fee volume -1000.000000000000000000 is negative")
+
+	_, err = NewFees("1000", "0", "0", "-5")
+	assert.EqualError(t, err, "daily buyback -5.000000000000000000 is negative")
+
+	_, err = NewFees("1000", "-0.5", "0.25", "0")
+	assert.EqualError(t, err, "burn ratio -0.500000000000000000 is not between 0 and 1")
+
+	_, err = NewFees("1000", "0", "1.5", "0")
+	assert.EqualError(t, err, "staker share 1.500000000000000000 is not between 0 and 1")
+}
+
+func TestRun(t *testing.T) {
+	params := mintingTypes.DefaultParams()
+	params.BlocksPerYear = mintModule.BLOCKS_PER_YEAR
+
+	vestingOnDays := map[int]sdk.Dec{0: sdk.NewDec(1000), 1: sdk.NewDec(1000)}
+	fees, err := NewFees("500", "0.5", "0.5", "0")
+	assert.Nil(t, err)
+
+	projected := Run(Config{
+		VestingOnDays: &vestingOnDays,
+		TotalSupply:   sdk.NewDec(1000000000000),
+		StakedTokens:  sdk.ZeroDec(),
+		BondedRatio:   params.GoalBonded,
+		Model:         mintModule.NewStandardModel(params, mintingTypes.DefaultInitialMinter()),
+		Fees:          fees,
+	})
+
+	assert.Equal(t, 3, len(projected.Rows))
+	assert.Equal(t, -1, projected.CapReachedOn)
+
+	// at the goal bonded ratio inflation does not move
+	for _, day := range projected.Rows {
+		assert.Equal(t, sdk.NewDecWithPrec(13, 2), day.Inflation)
+	}
+
+	// the unvesting tokens start out of circulation
+	assert.Equal(t, sdk.NewDec(999999998000), projected.Rows[0].CirculatingSupply)
+
+	// 24 hourly steps of 720 five second blocks minting 13% a year of the supply at the step, minus 250 burned
+	day := projected.Rows[1]
+	minted := sdk.NewDec(0)
+	for hour := 0; hour < 24; hour++ {
+		supply := sdk.NewDec(1000000000000).Add(minted)
+		minted = minted.Add(supply.Mul(sdk.NewDecWithPrec(13, 2)).QuoInt64(mintModule.BLOCKS_PER_YEAR).TruncateDec().MulInt64(720))
+	}
+	assert.Equal(t, minted.Sub(sdk.NewDec(250)), day.NetSupplyChange)
+	assert.Equal(t, minted.Add(sdk.NewDec(250)), day.StakingRewards)
+	assert.Equal(t, sdk.NewDec(250), day.TokensBurned)
+	assert.Equal(t, sdk.NewDec(500), day.FeesCollected)
+	assert.Equal(t, projected.Rows[0].CirculatingSupply.Add(day.NetSupplyChange).Add(sdk.NewDec(1000)), day.CirculatingSupply)
+}
+
+func TestRunHorizon(t *testing.T) {
+	params := mintingTypes.DefaultParams()
+	params.BlocksPerYear = mintModule.BLOCKS_PER_YEAR
+
+	// nothing unvests on the first days
+	vestingOnDays := map[int]sdk.Dec{3: sdk.NewDec(1000)}
+	config := Config{
+		VestingOnDays: &vestingOnDays,
+		TotalSupply:   sdk.NewDec(1000000000000),
+		StakedTokens:  sdk.NewDec(500000000000),
+		Model:         mintModule.NewStandardModel(params, mintingTypes.DefaultInitialMinter()),
+		Fees:          NoFees(),
+	}
+
+	projected := Run(config)
+	assert.Equal(t, 5, len(projected.Rows))
+	assert.Equal(t, sdk.NewDec(999999999000), projected.Rows[0].CirculatingSupply)
+
+	for i, day := range projected.Rows[1:] {
+		assert.Equal(t, i, day.Day)
+		assert.True(t, day.NetSupplyChange.IsPositive())
+	}
+
+	assert.Equal(t, sdk.NewDec(0), projected.Rows[3].TokensUnvesting)
+	assert.Equal(t, sdk.NewDec(1000), projected.Rows[4].TokensUnvesting)
+
+	// past the last unlock inflation keeps going
+	config.Days = 400
+	config.Model = mintModule.NewStandardModel(params, mintingTypes.DefaultInitialMinter())
+	projected = Run(config)
+	assert.Equal(t, 401, len(projected.Rows))
+	assert.Equal(t, 399, projected.Rows[400].Day)
+	assert.True(t, projected.Rows[400].TotalSupply.GT(projected.Rows[4].TotalSupply))
+}
+
+func TestRunGranularity(t *testing.T) {
+	params := mintingTypes.DefaultParams()
+	params.BlocksPerYear = mintModule.BLOCKS_PER_YEAR
+
+	vestingOnDays := map[int]sdk.Dec{0: sdk.NewDec(1000), 40: sdk.NewDec(2000)}
+	fees, err := NewFees("100", "0.5", "0", "0")
+	assert.Nil(t, err)
+
+	newConfig := func(granularity string) Config {
+		return Config{
+			VestingOnDays: &vestingOnDays,
+			TotalSupply:   sdk.NewDec(1000000000000),
+			StakedTokens:  sdk.NewDec(500000000000),
+			Model:         mintModule.NewStandardModel(params, mintingTypes.DefaultInitialMinter()),
+			Fees:          fees,
+			Granularity:   granularity,
+			Anchor:        1672531200, // 2023-01-01
+		}
+	}
+
+	daily := Run(newConfig(GRANULARITY_DAY))
+	assert.Equal(t, 42, len(daily.Rows))
+
+	// an hour row for every step plus the end of the day where unlocks and fees are settled
+	hourly := Run(newConfig(GRANULARITY_HOUR))
+	assert.Equal(t, 1+41*24, len(hourly.Rows))
+	assert.Equal(t, "hour 1", hourly.Rows[1].Label())
+
+	endOfDay := hourly.Rows[24]
+	assert.Equal(t, 24, endOfDay.Hour)
+	assert.Equal(t, sdk.NewDec(1000), endOfDay.TokensUnvesting)
+	// the last hour mints like the others and burns the fees of the day
+	assert.Equal(t, sdk.NewDec(50), endOfDay.TokensBurned)
+	assert.Equal(t, endOfDay.TotalSupply.Sub(hourly.Rows[23].TotalSupply), endOfDay.NetSupplyChange)
+	assert.Equal(t, daily.Rows[1].TotalSupply, endOfDay.TotalSupply)
+
+	netSupplyChange := sdk.NewDec(0)
+	for _, hour := range hourly.Rows[1:25] {
+		netSupplyChange = netSupplyChange.Add(hour.NetSupplyChange)
+	}
+	assert.Equal(t, daily.Rows[1].NetSupplyChange, netSupplyChange)
+
+	// minting block by block gives the same supply as minting every hour at once
+	blockConfig := newConfig(GRANULARITY_BLOCK)
+	blockConfig.Days = 2
+	blocks := Run(blockConfig)
+	assert.Equal(t, "hour 1 block 2", blocks.Rows[2].Label())
+	assert.Equal(t, daily.Rows[2].TotalSupply, blocks.Rows[len(blocks.Rows)-1].TotalSupply)
+
+	// a row for each of the 17280 5 second blocks of the day plus the end of the day
+	firstDay := 0
+	for _, row := range blocks.Rows[1:] {
+		if row.Day == 0 {
+			firstDay++
+		}
+	}
+	assert.Equal(t, 17280+1, firstDay)
+	assert.Equal(t, int64(1672531200+5), blocks.Rows[1].Time)
+	assert.Equal(t, "hour 24 block 720", blocks.Rows[17280].Label())
+	assert.Equal(t, int64(1672531200+86400), blocks.Rows[17280].Time)
+	assert.Equal(t, int64(1672531200+86400), blocks.Rows[17281].Time)
+	assert.Equal(t, 24, blocks.Rows[17281].Hour)
+
+	// with 7 second blocks the block that does not fit in the day is the first of the next one
+	blockConfig = newConfig(GRANULARITY_BLOCK)
+	blockConfig.Days = 2
+	blockConfig.BlockTime = 7 * time.Second
+	blocks = Run(blockConfig)
+
+	perDay := map[int]int{}
+	for _, row := range blocks.Rows[1:] {
+		if row.Block > 0 {
+			perDay[row.Day]++
+		}
+	}
+	assert.Equal(t, map[int]int{0: 12342, 1: 12343}, perDay)
+	assert.Equal(t, int64(1672531200+12343*7), blocks.Rows[12344].Time)
+
+	// january has 31 days and the 41st day is the 10th of february
+	monthly := Run(newConfig(GRANULARITY_MONTH))
+	assert.Equal(t, 3, len(monthly.Rows))
+	assert.Equal(t, "2023-01", monthly.Rows[1].Period)
+	assert.Equal(t, 30, monthly.Rows[1].Day)
+	assert.Equal(t, sdk.NewDec(3100), monthly.Rows[1].FeesCollected)
+	assert.Equal(t, sdk.NewDec(1000), monthly.Rows[1].TokensUnvesting)
+	assert.Equal(t, "2023-02", monthly.Rows[2].Period)
+	assert.Equal(t, sdk.NewDec(2000), monthly.Rows[2].TokensUnvesting)
+	assert.Equal(t, daily.Rows[41].TotalSupply, monthly.Rows[2].TotalSupply)
+	assert.Equal(t, daily.Rows[41].TotalSupply.Sub(daily.Rows[0].TotalSupply),
+		monthly.Rows[1].NetSupplyChange.Add(monthly.Rows[2].NetSupplyChange))
+
+	// cohorts are summed per month like the rest of the unlocks
+	cohortConfig := newConfig(GRANULARITY_MONTH)
+	cohortConfig.CohortVesting = map[string]map[int]sdk.Dec{
+		"team":     {0: sdk.NewDec(1000), 40: sdk.NewDec(500)},
+		"investor": {40: sdk.NewDec(1500)},
+	}
+	cohorts := Run(cohortConfig)
+	assert.Equal(t, sdk.NewDec(0), cohorts.Rows[0].CohortUnvesting["team"])
+	assert.Equal(t, sdk.NewDec(1000), cohorts.Rows[1].CohortUnvesting["team"])
+	assert.Equal(t, sdk.NewDec(0), cohorts.Rows[1].CohortUnvesting["investor"])
+	assert.Equal(t, sdk.NewDec(500), cohorts.Rows[2].CohortUnvesting["team"])
+	assert.Equal(t, sdk.NewDec(1500), cohorts.Rows[2].CohortUnvesting["investor"])
+	assert.Nil(t, monthly.Rows[1].CohortUnvesting)
+
+	// staked vesting tokens move from staked unvested to staked vested as they vest, the buckets add up to the total supply
+	delegatedVestingOnDays := map[int]sdk.Dec{40: sdk.NewDec(500)}
+	delegatedConfig := newConfig(GRANULARITY_DAY)
+	delegatedConfig.DelegatedVesting = sdk.NewDec(500)
+	delegatedConfig.DelegatedVestingOnDays = &delegatedVestingOnDays
+	delegated := Run(delegatedConfig)
+	for _, row := range []Row{delegated.Rows[0], delegated.Rows[40], delegated.Rows[41]} {
+		assert.Equal(t, row.TotalSupply, row.Liquid.Add(row.StakedVested).Add(row.StakedUnvested).Add(row.Locked))
+	}
+	assert.Equal(t, sdk.NewDec(500), delegated.Rows[0].StakedUnvested)
+	assert.Equal(t, sdk.NewDec(2500), delegated.Rows[0].Locked)
+	assert.Equal(t, sdk.NewDec(500000000000-500), delegated.Rows[0].StakedVested)
+	assert.Equal(t, sdk.NewDec(1500), delegated.Rows[40].Locked)
+	assert.True(t, delegated.Rows[41].StakedUnvested.IsZero())
+	assert.True(t, delegated.Rows[41].Locked.IsZero())
+	assert.Equal(t, sdk.NewDec(500000000000), delegated.Rows[41].StakedVested)
+
+	assert.Equal(t, "2023-W06", PeriodOf(40, GRANULARITY_WEEK, 1672531200))
+	assert.Equal(t, "2023-Q2", PeriodOf(90, GRANULARITY_QUARTER, 1672531200))
+	assert.NotNil(t, ValidGranularity("fortnight"))
+}
+
+func TestRunYear(t *testing.T) {
+	params := mintingTypes.DefaultParams()
+	params.BlocksPerYear = mintModule.BLOCKS_PER_YEAR
+
+	vestingOnDays := map[int]sdk.Dec{}
+	config := Config{
+		VestingOnDays: &vestingOnDays,
+		TotalSupply:   sdk.NewDec(1000000000000),
+		StakedTokens:  sdk.ZeroDec(),
+		BondedRatio:   params.GoalBonded,
+		Model:         mintModule.NewStandardModel(params, mintingTypes.DefaultInitialMinter()),
+		Fees:          NoFees(),
+		Days:          365,
+	}
+
+	// 13% a year recalculated from the supply every hour compounds to a little more than 13%
+	projected := Run(config)
+	growth := projected.Rows[365].TotalSupply.Quo(projected.Rows[0].TotalSupply).Sub(sdk.OneDec())
+	assert.True(t, growth.GT(sdk.NewDecWithPrec(13, 2)), growth.String())
+	assert.True(t, growth.LT(sdk.NewDecWithPrec(14, 2)), growth.String())
+
+	// an epoch model mints its whole epoch every day whatever the block time
+	for _, blockTime := range []time.Duration{time.Second * 5, time.Second * 61} {
+		config.Model = &mintModule.EpochReductionModel{
+			EpochProvisions: sdk.NewDec(17280000),
+			TailProvisions:  sdk.ZeroDec(),
+			EpochDuration:   time.Hour * 24,
+			ReductionFactor: sdk.OneDec(),
+			BlockTime:       blockTime,
+		}
+		config.BlockTime = blockTime
+
+		projected = Run(config)
+		minted := projected.Rows[365].TotalSupply.Sub(projected.Rows[0].TotalSupply)
+		off := minted.Sub(sdk.NewDec(17280000 * 365)).Abs().QuoInt64(17280000 * 365)
+		assert.True(t, off.LT(sdk.NewDecWithPrec(1, 3)), minted.String())
+	}
+}
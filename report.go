@@ -0,0 +1,84 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/brianosaurus/challenge2/projection"
+	reportModule "github.com/brianosaurus/challenge2/report"
+	scenarioModule "github.com/brianosaurus/challenge2/scenario"
+	stakingModule "github.com/brianosaurus/challenge2/staking"
+	vestingModule "github.com/brianosaurus/challenge2/vesting"
+)
+
+// NewReportData projects genesis with the scenario and collects everything the html report shows
+func NewReportData(genesis Genesis, scenario scenarioModule.Scenario) (reportModule.Data, error) {
+	appState := scenario.AppState(genesis.AppState)
+
+	config, err := scenario.Config(genesis.AppState)
+	if err != nil {
+		return reportModule.Data{}, err
+	}
+
+	genesis.SetHeights(&config)
+
+	continuousAccounts, delayedAccounts := vestingModule.GetVestingAccounts(appState)
+
+	return reportModule.Data{
+		ChainID:        genesis.ChainID,
+		GenesisHash:    genesis.Hash,
+		Scenario:       scenario.Name,
+		InflationModel: scenario.Profile(appState),
+		Projection:     projection.Run(config),
+		Holders:        vestingModule.GetHolders(appState, continuousAccounts, delayedAccounts),
+		Validators:     stakingModule.GetValidators(appState),
+	}, nil
+}
+
+// runReport writes a single html file with charts of the projection, the top holders and the validators
+func runReport(args []string) {
+	flags := flag.NewFlagSet("report", flag.ExitOnError)
+	out := flags.String("out", "genesis_report.html", "the html file to write the report to")
+	genesisFile := flags.String("genesis", "genesis.json", "the genesis file to analyze")
+	scenarioFile := flags.String("scenario", "", "a scenario file with the assumptions of the projection (the first scenario is used)")
+	topHolders := flags.Int("top-holders", reportModule.TOP_HOLDERS, "the number of holders to list")
+	topValidators := flags.Int("top-validators", reportModule.TOP_VALIDATORS, "the number of validators to list")
+	flags.Parse(args)
+
+	scenario, err := firstScenario(*scenarioFile)
+	if err != nil {
+		fmt.Println("Error reading scenario file:", err)
+		return
+	}
+
+	genesis, err := ReadGenesis(*genesisFile)
+	if err != nil {
+		fmt.Println("Error reading genesis file:", err)
+		return
+	}
+
+	data, err := NewReportData(genesis, scenario)
+	if err != nil {
+		fmt.Println("Error in scenario:", err)
+		return
+	}
+
+	data.TopHolders = *topHolders
+	data.TopValidators = *topValidators
+
+	file, err := os.Create(*out)
+	if err != nil {
+		fmt.Println("Error creating html file")
+		return
+	}
+	defer file.Close()
+
+	err = reportModule.Render(file, data)
+	if err != nil {
+		fmt.Println("Error writing report:", err)
+		return
+	}
+
+	fmt.Printf("\nDone\n")
+}
@@ -0,0 +1,278 @@
+package report
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/brianosaurus/challenge2/chart"
+	"github.com/brianosaurus/challenge2/projection"
+	stakingModule "github.com/brianosaurus/challenge2/staking"
+	vestingModule "github.com/brianosaurus/challenge2/vesting"
+)
+
+const (
+	TOP_HOLDERS    = 20
+	TOP_VALIDATORS = 20
+
+	CHART_SUPPLY     = "supply"
+	CHART_INFLATION  = "inflation"
+	CHART_UNLOCKS    = "unlocks"
+	CHART_REWARDS    = "rewards"
+	CHART_HOLDERS    = "holders"
+	CHART_VALIDATORS = "validators"
+)
+
+var CHARTS = []string{CHART_SUPPLY, CHART_INFLATION, CHART_UNLOCKS, CHART_REWARDS, CHART_HOLDERS, CHART_VALIDATORS}
+
+// Data is everything that goes into the report
+type Data struct {
+	ChainID        string
+	GenesisHash    string
+	Scenario       string
+	InflationModel string
+	Projection     projection.Projection
+	Holders        []vestingModule.Holder
+	Validators     []stakingModule.Validator
+	// the number of holders and validators to list, TOP_HOLDERS and TOP_VALIDATORS when 0
+	TopHolders    int
+	TopValidators int
+}
+
+// Share is a holder or validator row of the report with its share of the total
+type Share struct {
+	Name       string
+	Amount     string
+	Percent    string
+	Vesting    bool
+	Commission string
+	Jailed     bool
+}
+
+type page struct {
+	Data
+	Generated         string
+	Anchor            string
+	LastDate          string
+	GenesisSupply     string
+	CirculatingSupply string
+	FinalSupply       string
+	FinalCirculating  string
+	PeakInflation     string
+	PeakInflationDate string
+	Charts            []template.HTML
+	Holders           []Share
+	Validators        []Share
+}
+
+// Charts returns the charts of the report: supply, inflation, unlocks, rewards, holders and validators
+func Charts(data Data) []chart.Chart {
+	charts := []chart.Chart{}
+	for _, name := range CHARTS {
+		c, _ := Chart(name, data)
+		charts = append(charts, c)
+	}
+
+	return charts
+}
+
+// Chart returns one of the CHARTS drawn from the data
+func Chart(name string, data Data) (chart.Chart, error) {
+	rows := data.Projection.Rows
+
+	labels := make([]string, len(rows))
+	for i, row := range rows {
+		labels[i] = data.Projection.Date(row).Format("2006-01-02")
+	}
+
+	column := func(value func(row projection.Row) sdk.Dec) []float64 {
+		values := make([]float64, len(rows))
+		for i, row := range rows {
+			values[i] = value(row).MustFloat64()
+		}
+
+		return values
+	}
+
+	switch name {
+	case CHART_SUPPLY:
+		return chart.LineChart{Title: "Circulating vs Total Supply", Labels: labels, Series: []chart.Series{
+			{Name: "Circulating Supply", Values: column(func(row projection.Row) sdk.Dec { return row.CirculatingSupply })},
+			{Name: "Total Supply", Values: column(func(row projection.Row) sdk.Dec { return row.TotalSupply })},
+		}}, nil
+	case CHART_INFLATION:
+		return chart.LineChart{Title: "Inflation (%)", Labels: labels, Series: []chart.Series{
+			{Name: "Inflation", Values: column(func(row projection.Row) sdk.Dec { return row.Inflation.MulInt64(100) })},
+		}}, nil
+	case CHART_UNLOCKS:
+		return chart.BarChart{Title: "Tokens Unlocking", Labels: labels,
+			Values: column(func(row projection.Row) sdk.Dec { return row.TokensUnvesting })}, nil
+	case CHART_REWARDS:
+		return chart.LineChart{Title: "Cumulative Staking Rewards", Labels: labels, Series: []chart.Series{
+			{Name: "Staking Rewards", Values: column(func(row projection.Row) sdk.Dec { return row.StakingRewards })},
+		}}, nil
+	case CHART_HOLDERS:
+		holders := chart.BarChart{Title: "Top Holders", Horizontal: true}
+		for _, holder := range top(data.Holders, data.TopHolders, TOP_HOLDERS) {
+			holders.Labels = append(holders.Labels, holder.Address)
+			holders.Values = append(holders.Values, holder.Amount.MustFloat64())
+		}
+
+		return holders, nil
+	case CHART_VALIDATORS:
+		validators := chart.BarChart{Title: "Validator Distribution", Horizontal: true}
+		for _, validator := range top(data.Validators, data.TopValidators, TOP_VALIDATORS) {
+			validators.Labels = append(validators.Labels, validatorName(validator))
+			validators.Values = append(validators.Values, validator.Tokens.MustFloat64())
+		}
+
+		return validators, nil
+	}
+
+	return nil, fmt.Errorf("unknown chart %q, expected one of %v", name, CHARTS)
+}
+
+// Render writes the report as a single html file. The charts are inline svg so the file needs
+// nothing else to be opened.
+func Render(writer io.Writer, data Data) error {
+	p := page{Data: data, Generated: time.Now().UTC().Format(time.RFC3339)}
+
+	rows := data.Projection.Rows
+	if len(rows) > 0 {
+		first, last := rows[0], rows[len(rows)-1]
+
+		p.Anchor = data.Projection.Date(first).Format("2006-01-02")
+		p.LastDate = data.Projection.Date(last).Format("2006-01-02")
+		p.GenesisSupply = first.TotalSupply.RoundInt().String()
+		p.CirculatingSupply = first.CirculatingSupply.RoundInt().String()
+		p.FinalSupply = last.TotalSupply.RoundInt().String()
+		p.FinalCirculating = last.CirculatingSupply.RoundInt().String()
+
+		peak := first
+		for _, row := range rows {
+			if row.Inflation.GT(peak.Inflation) {
+				peak = row
+			}
+		}
+
+		p.PeakInflation = percent(peak.Inflation)
+		p.PeakInflationDate = data.Projection.Date(peak).Format("2006-01-02")
+	}
+
+	for _, c := range Charts(data) {
+		// the svg is made by the chart package and every label in it is escaped
+		p.Charts = append(p.Charts, template.HTML(chart.SVG(c)))
+	}
+
+	supply := sdk.ZeroDec()
+	for _, holder := range data.Holders {
+		supply = supply.Add(holder.Amount)
+	}
+
+	for _, holder := range top(data.Holders, data.TopHolders, TOP_HOLDERS) {
+		p.Holders = append(p.Holders, Share{Name: holder.Address, Amount: holder.Amount.RoundInt().String(),
+			Percent: percent(ratio(holder.Amount, supply)), Vesting: holder.Vesting})
+	}
+
+	staked := sdk.ZeroDec()
+	for _, validator := range data.Validators {
+		staked = staked.Add(validator.Tokens)
+	}
+
+	for _, validator := range top(data.Validators, data.TopValidators, TOP_VALIDATORS) {
+		p.Validators = append(p.Validators, Share{Name: validatorName(validator), Amount: validator.Tokens.RoundInt().String(),
+			Percent: percent(ratio(validator.Tokens, staked)), Commission: percent(validator.CommissionRate), Jailed: validator.Jailed})
+	}
+
+	return pageTemplate.Execute(writer, p)
+}
+
+func top[T any](items []T, n int, defaultN int) []T {
+	if n <= 0 {
+		n = defaultN
+	}
+
+	if len(items) < n {
+		return items
+	}
+
+	return items[:n]
+}
+
+func validatorName(validator stakingModule.Validator) string {
+	if validator.Moniker != "" {
+		return validator.Moniker
+	}
+
+	return validator.OperatorAddress
+}
+
+func ratio(amount sdk.Dec, total sdk.Dec) sdk.Dec {
+	if total.IsZero() {
+		return sdk.ZeroDec()
+	}
+
+	return amount.Quo(total)
+}
+
+// percent writes a ratio as a percentage with two decimals
+func percent(value sdk.Dec) string {
+	if value.IsNil() {
+		return ""
+	}
+
+	return fmt.Sprintf("%.2f%%", value.MustFloat64()*100)
+}
+
+var pageTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Genesis Report{{if .ChainID}} - {{.ChainID}}{{end}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em auto; max-width: 860px; color: #222222; }
+h1, h2 { font-weight: normal; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 2em; }
+th, td { text-align: left; padding: 4px 8px; border-bottom: 1px solid #dddddd; }
+td.number { text-align: right; font-family: monospace; }
+.chart { margin-bottom: 2em; }
+.muted { color: #777777; }
+</style>
+</head>
+<body>
+<h1>Genesis Report{{if .ChainID}} for {{.ChainID}}{{end}}</h1>
+<p class="muted">Generated {{.Generated}}{{if .GenesisHash}} from genesis {{.GenesisHash}}{{end}}{{if .Scenario}} with scenario {{.Scenario}}{{end}}</p>
+
+<h2>Summary</h2>
+<table>
+<tr><th>Inflation model</th><td>{{.InflationModel}}</td></tr>
+<tr><th>Projected</th><td>{{.Anchor}} to {{.LastDate}}</td></tr>
+<tr><th>Genesis total supply</th><td class="number">{{.GenesisSupply}}</td></tr>
+<tr><th>Circulating supply on {{.Anchor}}</th><td class="number">{{.CirculatingSupply}}</td></tr>
+<tr><th>Total supply on {{.LastDate}}</th><td class="number">{{.FinalSupply}}</td></tr>
+<tr><th>Circulating supply on {{.LastDate}}</th><td class="number">{{.FinalCirculating}}</td></tr>
+<tr><th>Peak inflation</th><td>{{.PeakInflation}} on {{.PeakInflationDate}}</td></tr>
+{{if ge .Projection.CapReachedOn 0}}<tr><th>Max supply reached</th><td>day {{.Projection.CapReachedOn}}</td></tr>
+{{end}}</table>
+
+<h2>Charts</h2>
+{{range .Charts}}<div class="chart">
+{{.}}</div>
+{{end}}
+<h2>Top Holders</h2>
+<table>
+<tr><th>Address</th><th>Amount</th><th>Share</th><th>Vesting</th></tr>
+{{range .Holders}}<tr><td>{{.Name}}</td><td class="number">{{.Amount}}</td><td class="number">{{.Percent}}</td><td>{{if .Vesting}}yes{{end}}</td></tr>
+{{end}}</table>
+
+<h2>Validators</h2>
+<table>
+<tr><th>Validator</th><th>Tokens</th><th>Share</th><th>Commission</th></tr>
+{{range .Validators}}<tr><td>{{.Name}}{{if .Jailed}} (jailed){{end}}</td><td class="number">{{.Amount}}</td><td class="number">{{.Percent}}</td><td class="number">{{.Commission}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
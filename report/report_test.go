@@ -0,0 +1,66 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	mintingTypes "github.com/cosmos/cosmos-sdk/x/mint/types"
+	"github.com/stretchr/testify/assert"
+
+	mintModule "github.com/brianosaurus/challenge2/mint"
+	"github.com/brianosaurus/challenge2/projection"
+	stakingModule "github.com/brianosaurus/challenge2/staking"
+	vestingModule "github.com/brianosaurus/challenge2/vesting"
+)
+
+func TestRender(t *testing.T) {
+	vestingOnDays := map[int]sdk.Dec{0: sdk.NewDec(1000), 5: sdk.NewDec(5000)}
+	projected := projection.Run(projection.Config{
+		VestingOnDays: &vestingOnDays,
+		TotalSupply:   sdk.NewDec(1000000000000),
+		StakedTokens:  sdk.NewDec(500000000000),
+		Model:         mintModule.NewStandardModel(mintingTypes.DefaultParams(), mintingTypes.DefaultInitialMinter()),
+		Fees:          projection.NoFees(),
+		Anchor:        1669100000,
+	})
+
+	data := Data{
+		ChainID:        "umee-1",
+		GenesisHash:    "abc123",
+		InflationModel: "cosmos",
+		Projection:     projected,
+		Holders: []vestingModule.Holder{
+			{Address: "umee1big", Amount: sdk.NewDec(750), Vesting: true},
+			{Address: "umee1small", Amount: sdk.NewDec(250)},
+		},
+		Validators: []stakingModule.Validator{
+			{Moniker: "<script>alert(1)</script>", Tokens: sdk.NewDec(300), CommissionRate: sdk.NewDecWithPrec(5, 2)},
+			{OperatorAddress: "umeevaloper1jailed", Tokens: sdk.NewDec(100), Jailed: true},
+		},
+		TopHolders: 1,
+	}
+
+	assert.Equal(t, 6, len(Charts(data)))
+
+	var buf bytes.Buffer
+	err := Render(&buf, data)
+	assert.Nil(t, err)
+
+	html := buf.String()
+	assert.Contains(t, html, "Genesis Report for umee-1")
+	assert.Contains(t, html, "2022-11-22 to 2022-11-27")
+	assert.Contains(t, html, "<td>umee1big</td><td class=\"number\">750</td><td class=\"number\">75.00%</td><td>yes</td>")
+	assert.NotContains(t, html, "umee1small")
+	assert.Contains(t, html, "umeevaloper1jailed (jailed)")
+	assert.Contains(t, html, "5.00%")
+	assert.Equal(t, 6, strings.Count(html, "<svg "))
+
+	// the monikers come from genesis so they must not end up as html
+	assert.NotContains(t, html, "<script>")
+
+	// everything is in the one file
+	assert.NotContains(t, html, "src=")
+	assert.NotContains(t, html, "href=")
+}
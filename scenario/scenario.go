@@ -0,0 +1,320 @@
+package scenario
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"sigs.k8s.io/yaml"
+
+	mintModule "github.com/brianosaurus/challenge2/mint"
+	"github.com/brianosaurus/challenge2/projection"
+	stakingModule "github.com/brianosaurus/challenge2/staking"
+	vestingModule "github.com/brianosaurus/challenge2/vesting"
+)
+
+// Fees are the fee settings of a scenario, see projection.Fees
+type Fees struct {
+	DailyVolume  string `json:"daily_volume"`
+	BurnRatio    string `json:"burn_ratio"`
+	StakerShare  string `json:"staker_share"`
+	DailyBuyback string `json:"daily_buyback"`
+}
+
+// Scenario is a set of assumptions to run the projection with. Anything left empty is taken from
+// genesis or the defaults of the analyzer.
+type Scenario struct {
+	Name string `json:"name"`
+	// one of cosmos, osmosis, evmos or juno, detected from genesis if empty
+	InflationModel string `json:"inflation_model"`
+	// merged over app_state.mint of the genesis, e.g. {"params": {"goal_bonded": "0.5"}}
+	Mint        map[string]interface{} `json:"mint"`
+	MaxSupply   string                 `json:"max_supply"`
+	TaperSupply string                 `json:"taper_supply"`
+	// a fixed bonded ratio instead of the one from the staked tokens
+	BondedRatio string `json:"bonded_ratio"`
+	// the staked tokens instead of the ones from the gen_txs in genesis
+	StakedTokens string `json:"staked_tokens"`
+	// seconds per block, the default of the analyzer when nil
+	BlockTime *float64 `json:"block_time"`
+	// the day the projection starts from, RFC3339 or YYYY-MM-DD
+	Anchor string `json:"anchor"`
+	// how many days to project, or the date to project until. Defaults to the last unlock
+	Days  int    `json:"days"`
+	Until string `json:"until"`
+	// block, hour, day, week, month or quarter
+	Granularity string `json:"granularity"`
+	Fees        Fees   `json:"fees"`
+}
+
+type file struct {
+	Scenarios []Scenario `json:"scenarios"`
+}
+
+// Load reads the scenarios from a YAML or JSON file. The file either has a list of scenarios
+// under "scenarios" or is a single scenario. Scenarios without a name are named after the file.
+func Load(path string) ([]Scenario, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// YAML is a superset of JSON so both go through the same path
+	rawJson, err := yaml.YAMLToJSON(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	var scenarios file
+	if err = json.Unmarshal(rawJson, &scenarios); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	if len(scenarios.Scenarios) == 0 {
+		var scenario Scenario
+		if err = json.Unmarshal(rawJson, &scenario); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		scenarios.Scenarios = []Scenario{scenario}
+	}
+
+	baseName := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+
+	for i := range scenarios.Scenarios {
+		if scenarios.Scenarios[i].Name != "" {
+			continue
+		}
+
+		scenarios.Scenarios[i].Name = baseName
+		if len(scenarios.Scenarios) > 1 {
+			scenarios.Scenarios[i].Name = fmt.Sprintf("%s-%d", baseName, i+1)
+		}
+	}
+
+	return scenarios.Scenarios, nil
+}
+
+// AnchorTime is the unix time the projection starts from
+func (s Scenario) AnchorTime() (int64, error) {
+	if s.Anchor == "" {
+		return vestingModule.TheTime, nil
+	}
+
+	anchor, err := ParseTime(s.Anchor)
+	if err != nil {
+		return 0, fmt.Errorf("invalid anchor: %w", err)
+	}
+
+	return anchor.Unix(), nil
+}
+
+// Horizon is the number of days to project, zero means up to the last unlock
+func (s Scenario) Horizon(anchor int64) (int, error) {
+	if s.Until == "" {
+		return s.Days, nil
+	}
+
+	until, err := ParseTime(s.Until)
+	if err != nil {
+		return 0, fmt.Errorf("invalid until: %w", err)
+	}
+
+	if until.Unix() <= anchor {
+		return 0, fmt.Errorf("until %s is not after the anchor", s.Until)
+	}
+
+	// a partial day at the end still gets its own row
+	return int((until.Unix() - anchor + 86399) / 86400), nil
+}
+
+// ParseTime reads a time written as RFC3339 or YYYY-MM-DD (midnight UTC)
+func ParseTime(str string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		parsed, err := time.Parse(layout, str)
+		if err == nil {
+			return parsed, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("%q is not RFC3339 or YYYY-MM-DD", str)
+}
+
+// Config builds the projection for the genesis app state with the assumptions of the scenario
+func (s Scenario) Config(appState map[string]interface{}) (projection.Config, error) {
+	config := projection.Config{BlockTime: s.blockTime(), Granularity: s.Granularity}
+
+	if err := projection.ValidGranularity(s.Granularity); err != nil {
+		return config, err
+	}
+
+	// a block time that rounds down to zero nanoseconds would never mint
+	if s.BlockTime != nil && s.blockTime() <= 0 {
+		return config, fmt.Errorf("block_time %v is not positive", *s.BlockTime)
+	}
+
+	anchor, err := s.AnchorTime()
+	if err != nil {
+		return config, err
+	}
+
+	config.Anchor = anchor
+
+	config.Days, err = s.Horizon(anchor)
+	if err != nil {
+		return config, err
+	}
+
+	fees := s.Fees
+	fees.DailyVolume = orDefault(fees.DailyVolume, "0")
+	fees.BurnRatio = orDefault(fees.BurnRatio, "0")
+	fees.StakerShare = orDefault(fees.StakerShare, "0")
+	fees.DailyBuyback = orDefault(fees.DailyBuyback, "0")
+
+	config.Fees, err = projection.NewFees(fees.DailyVolume, fees.BurnRatio, fees.StakerShare, fees.DailyBuyback)
+	if err != nil {
+		return config, err
+	}
+
+	continuousVestingAccounts, delayedVestingAccounts := vestingModule.GetVestingAccounts(appState)
+	config.TotalSupply, config.VestingOnDays = vestingModule.GetTotalSupplyAndVestingScheduleAt(appState,
+		continuousVestingAccounts, delayedVestingAccounts, anchor)
+
+	// the gen_tx delegations of vesting accounts are not in their delegated_vesting and delegated_free yet
+	vestingAccounts := vestingModule.GetAccounts(continuousVestingAccounts, delayedVestingAccounts)
+	config.DelegatedVesting, config.DelegatedVestingOnDays = vestingModule.GetDelegatedVestingAt(vestingAccounts,
+		stakingModule.GetDelegations(appState), anchor)
+
+	if s.StakedTokens != "" {
+		if config.StakedTokens, err = sdk.NewDecFromStr(s.StakedTokens); err != nil {
+			return config, fmt.Errorf("invalid staked tokens: %w", err)
+		}
+	} else {
+		config.StakedTokens = stakingModule.GetStakedTokens(appState).Add(vestingModule.GetDelegated(vestingAccounts))
+	}
+
+	if s.BondedRatio != "" {
+		if config.BondedRatio, err = sdk.NewDecFromStr(s.BondedRatio); err != nil {
+			return config, fmt.Errorf("invalid bonded ratio: %w", err)
+		}
+	}
+
+	config.Model, err = s.Model(appState)
+	if err != nil {
+		return config, err
+	}
+
+	return config, nil
+}
+
+func (s Scenario) blockTime() time.Duration {
+	if s.BlockTime == nil {
+		return 0
+	}
+
+	return time.Duration(*s.BlockTime * float64(time.Second))
+}
+
+// Model builds a new inflation model for the scenario. Models keep state while the projection runs
+// so every projection needs its own.
+func (s Scenario) Model(appState map[string]interface{}) (model mintModule.InflationModel, err error) {
+	appState = s.AppState(appState)
+
+	// the genesis parsers panic on bad input, scenario files are user input so turn that into an error
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("scenario %s: %v", s.Name, r)
+		}
+	}()
+
+	model = mintModule.GetInflationModel(appState, s.InflationModel)
+
+	if s.MaxSupply != "" {
+		maxSupply, ok := sdk.NewIntFromString(s.MaxSupply)
+		if !ok {
+			return nil, fmt.Errorf("invalid max supply %q", s.MaxSupply)
+		}
+
+		taperSupply, ok := sdk.NewIntFromString(orDefault(s.TaperSupply, "0"))
+		if !ok {
+			return nil, fmt.Errorf("invalid taper supply %q", s.TaperSupply)
+		}
+
+		model = mintModule.NewCappedModel(model, maxSupply, taperSupply)
+	}
+
+	if s.blockTime() > 0 {
+		mintModule.SetBlockTime(model, s.blockTime())
+	}
+
+	return model, nil
+}
+
+// AppState is the genesis app state with the mint overrides of the scenario applied
+func (s Scenario) AppState(appState map[string]interface{}) map[string]interface{} {
+	if s.Mint == nil {
+		return appState
+	}
+
+	// only copy what changes so the genesis can be shared between scenarios
+	overridden := make(map[string]interface{}, len(appState))
+	for key, value := range appState {
+		overridden[key] = value
+	}
+
+	mint, _ := appState["mint"].(map[string]interface{})
+	overridden["mint"] = merge(mint, s.Mint)
+
+	return overridden
+}
+
+// Profile is the inflation model the scenario runs with
+func (s Scenario) Profile(appState map[string]interface{}) string {
+	if s.InflationModel != "" {
+		return s.InflationModel
+	}
+
+	return mintModule.DetectProfile(appState)
+}
+
+// merge returns a copy of base with the values of overrides replacing its own, nested objects are merged.
+// Genesis keeps numbers in strings so numbers from the scenario file are turned into strings.
+func merge(base map[string]interface{}, overrides map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(base)+len(overrides))
+
+	for key, value := range base {
+		merged[key] = value
+	}
+
+	for key, value := range overrides {
+		baseValue, baseIsMap := merged[key].(map[string]interface{})
+
+		switch overrideValue := value.(type) {
+		case map[string]interface{}:
+			if baseIsMap {
+				merged[key] = merge(baseValue, overrideValue)
+			} else {
+				merged[key] = overrideValue
+			}
+		case float64:
+			merged[key] = strconv.FormatFloat(overrideValue, 'f', -1, 64)
+		default:
+			merged[key] = value
+		}
+	}
+
+	return merged
+}
+
+func orDefault(value string, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+
+	return value
+}
@@ -0,0 +1,206 @@
+package scenario
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+
+	mintModule "github.com/brianosaurus/challenge2/mint"
+	"github.com/brianosaurus/challenge2/projection"
+)
+
+const (
+	APP_STATE =
+`{
+	"auth": {
+		"accounts": [
+			{
+				"@type": "/cosmos.vesting.v1beta1.DelayedVestingAccount",
+				"base_vesting_account": {
+					"base_account": {
+						"address": "umee1agzky2ak6xs5vve3c2wzjtqdq7fwadcgj2mxf9",
+						"pub_key": null,
+						"account_number": "0",
+						"sequence": "0"
+					},
+					"original_vesting": [
+						{
+							"denom": "uumee",
+							"amount": "309282000000"
+						}
+					],
+					"delegated_free": [],
+					"delegated_vesting": [],
+					"end_time": "1676480400"
+				}
+			}
+		]
+	},
+	"bank": {
+		"balances": [
+			{
+				"address": "umee1qqq8fdsrcsz4jnlvrcqa6ds2ruflgrgeguyeh0",
+				"coins": [
+					{
+						"denom": "uumee",
+						"amount": "8333000000"
+					}
+				]
+			}
+		]
+	},
+	"genutil": {
+		"gen_txs": []
+	},
+	"mint": {
+		"minter": {
+			"inflation": "0.130000000000000000",
+			"annual_provisions": "0.000000000000000000"
+		},
+		"params": {
+			"mint_denom": "uumee",
+			"inflation_rate_change": "1.000000000000000000",
+			"inflation_max": "0.140000000000000000",
+			"inflation_min": "0.070000000000000000",
+			"goal_bonded": "0.330000000000000000",
+			"blocks_per_year": "4360000"
+		}
+	}
+}`
+
+	SCENARIOS_YAML =
+`scenarios:
+  - name: high-goal
+    mint:
+      params:
+        goal_bonded: 0.5
+        inflation_max: "0.2"
+    bonded_ratio: "0.25"
+    block_time: 6
+    anchor: 2022-11-22
+  - anchor: "2022-11-22T00:00:00Z"
+    fees:
+      daily_volume: "1000"
+      burn_ratio: "0.5"
+`
+
+	SCENARIO_JSON =
+`{
+	"name": "capped",
+	"max_supply": "400000000000"
+}`
+)
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	yamlPath := filepath.Join(dir, "what-if.yaml")
+	jsonPath := filepath.Join(dir, "capped.json")
+	assert.Nil(t, os.WriteFile(yamlPath, []byte(SCENARIOS_YAML), 0644))
+	assert.Nil(t, os.WriteFile(jsonPath, []byte(SCENARIO_JSON), 0644))
+
+	scenarios, err := Load(yamlPath)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(scenarios))
+	assert.Equal(t, "high-goal", scenarios[0].Name)
+	assert.Equal(t, "what-if-2", scenarios[1].Name)
+	assert.Equal(t, float64(6), *scenarios[0].BlockTime)
+	assert.Nil(t, scenarios[1].BlockTime)
+	assert.Equal(t, "1000", scenarios[1].Fees.DailyVolume)
+
+	anchor, err := scenarios[0].AnchorTime()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1669075200), anchor)
+
+	horizon, err := Scenario{Until: "2022-11-23T12:00:00Z"}.Horizon(anchor)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, horizon)
+
+	horizon, err = Scenario{Days: 30}.Horizon(anchor)
+	assert.Nil(t, err)
+	assert.Equal(t, 30, horizon)
+
+	_, err = Scenario{Until: "2022-11-01"}.Horizon(anchor)
+	assert.NotNil(t, err)
+
+	capped, err := Load(jsonPath)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(capped))
+	assert.Equal(t, "capped", capped[0].Name)
+	assert.Equal(t, "400000000000", capped[0].MaxSupply)
+}
+
+func TestConfig(t *testing.T) {
+	var appState = make(map[string]interface{})
+
+	err := json.Unmarshal([]byte(APP_STATE), &appState)
+	if err != nil {
+		t.Log("Error decoding json")
+		t.FailNow()
+	}
+
+	dir := t.TempDir()
+	yamlPath := filepath.Join(dir, "what-if.yaml")
+	assert.Nil(t, os.WriteFile(yamlPath, []byte(SCENARIOS_YAML), 0644))
+
+	scenarios, err := Load(yamlPath)
+	assert.Nil(t, err)
+
+	config, err := scenarios[0].Config(appState)
+	assert.Nil(t, err)
+
+	model := config.Model.(*mintModule.StandardModel)
+	assert.Equal(t, sdk.MustNewDecFromStr("0.5"), model.Params.GoalBonded)
+	assert.Equal(t, sdk.MustNewDecFromStr("0.2"), model.Params.InflationMax)
+	// 6 second blocks do not change what the chain mints per block
+	assert.Equal(t, uint64(4360000), model.Params.BlocksPerYear)
+	assert.Equal(t, 6*time.Second, config.BlockTime)
+	assert.Equal(t, sdk.MustNewDecFromStr("0.25"), config.BondedRatio)
+	assert.Equal(t, sdk.NewDec(317615000000), config.TotalSupply)
+
+	// anchored on 2022-11-22 the delayed account unlocks 85 days later
+	_, ok := (*config.VestingOnDays)[85]
+	assert.True(t, ok)
+
+	// the overrides do not leak into the genesis
+	params := appState["mint"].(map[string]interface{})["params"].(map[string]interface{})
+	assert.Equal(t, "0.330000000000000000", params["goal_bonded"])
+
+	config, err = scenarios[1].Config(appState)
+	assert.Nil(t, err)
+	assert.Equal(t, sdk.NewDec(500), config.Fees.Burned())
+
+	scenarios[1].Mint = map[string]interface{}{"params": map[string]interface{}{"goal_bonded": "lots"}}
+	_, err = scenarios[1].Config(appState)
+	assert.NotNil(t, err)
+}
+
+func TestConfigBlockTime(t *testing.T) {
+	var appState = make(map[string]interface{})
+
+	err := json.Unmarshal([]byte(APP_STATE), &appState)
+	if err != nil {
+		t.Log("Error decoding json")
+		t.FailNow()
+	}
+
+	for _, blockTime := range []float64{0, -5, 1e-10} {
+		blockTime := blockTime
+		_, err = Scenario{Anchor: "2022-11-22", Days: 1, BlockTime: &blockTime}.Config(appState)
+		assert.NotNil(t, err, blockTime)
+	}
+
+	// blocks slower than a minute still mint
+	blockTime := float64(61)
+	config, err := Scenario{Anchor: "2022-11-22", Days: 1, BlockTime: &blockTime}.Config(appState)
+	assert.Nil(t, err)
+	assert.Equal(t, 61*time.Second, config.BlockTime)
+
+	projected := projection.Run(config)
+	assert.True(t, projected.Rows[len(projected.Rows)-1].StakingRewards.IsPositive())
+}
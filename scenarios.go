@@ -0,0 +1,186 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/brianosaurus/challenge2/projection"
+	scenarioModule "github.com/brianosaurus/challenge2/scenario"
+)
+
+// comparable checks the projections share an anchor and granularity, only then do their rows cover the same periods
+func comparable(names []string, projections []projection.Projection) error {
+	for i := 1; i < len(projections); i++ {
+		if projections[i].Anchor != projections[0].Anchor {
+			return fmt.Errorf("scenario %s is anchored at %s and %s at %s", names[i],
+				time.Unix(projections[i].Anchor, 0).UTC().Format(time.RFC3339), names[0],
+				time.Unix(projections[0].Anchor, 0).UTC().Format(time.RFC3339))
+		}
+
+		if projections[i].Granularity != projections[0].Granularity {
+			return fmt.Errorf("scenario %s has granularity %s and %s has %s", names[i], projections[i].Granularity,
+				names[0], projections[0].Granularity)
+		}
+	}
+
+	return nil
+}
+
+// WriteComparisonCSV writes the projections of several scenarios side by side. The projections need the same
+// anchor and granularity so rows line up by their position, a scenario that projects fewer days has empty
+// columns at the end.
+func WriteComparisonCSV(writer *csv.Writer, names []string, projections []projection.Projection) error {
+	if err := comparable(names, projections); err != nil {
+		return err
+	}
+
+	periods := len(projections) > 0 && hasPeriods(projections[0])
+
+	header := []string{"Days Since Genesis Analyzed", "Date"}
+	if periods {
+		header = append(header, "Period")
+	}
+
+	for _, name := range names {
+		header = append(header, name+" Inflation", name+" Staking Rewards", name+" Circulating Supply", name+" Total Supply")
+	}
+
+	err := writer.Write(header)
+	if err != nil {
+		return err
+	}
+
+	rows := 0
+	for _, projected := range projections {
+		if len(projected.Rows) > rows {
+			rows = len(projected.Rows)
+		}
+	}
+
+	for row := 0; row < rows; row++ {
+		csvStr := []string{"", ""}
+		if periods {
+			csvStr = append(csvStr, "")
+		}
+
+		for _, projected := range projections {
+			if row >= len(projected.Rows) {
+				csvStr = append(csvStr, "", "", "", "")
+				continue
+			}
+
+			day := projected.Rows[row]
+			if csvStr[0] == "" {
+				csvStr[0] = projected.DayColumn(row)
+				csvStr[1] = projected.Date(day).Format("2006-01-02")
+				if periods {
+					csvStr[2] = day.Label()
+				}
+			}
+
+			csvStr = append(csvStr, day.Inflation.String(), day.StakingRewards.RoundInt().String(),
+				day.CirculatingSupply.RoundInt().String(), day.TotalSupply.RoundInt().String())
+		}
+
+		writer.Write(csvStr)
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// runScenarios projects the genesis once for every scenario in the scenario files and writes one comparison csv
+func runScenarios(args []string) {
+	flags := flag.NewFlagSet("scenarios", flag.ExitOnError)
+	csvStr := flags.String("csv", "scenario_comparison.csv", "the csv file to output the comparison to")
+	genesisFile := flags.String("genesis", "genesis.json", "the genesis file to analyze")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage of %s scenarios [flags] scenario.yaml [scenario.json ...]:\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return
+	}
+
+	var scenarios []scenarioModule.Scenario
+
+	for _, path := range flags.Args() {
+		loaded, err := scenarioModule.Load(path)
+		if err != nil {
+			fmt.Println("Error reading scenario file:", err)
+			return
+		}
+
+		scenarios = append(scenarios, loaded...)
+	}
+
+	genesis, err := ReadGenesis(*genesisFile)
+	if err != nil {
+		fmt.Println("Error reading genesis file:", err)
+		return
+	}
+
+	appState := genesis.AppState
+
+	names := make([]string, 0, len(scenarios))
+	projections := make([]projection.Projection, 0, len(scenarios))
+
+	for _, scenario := range scenarios {
+		config, err := scenario.Config(appState)
+		if err != nil {
+			fmt.Printf("Error in scenario %s: %v\n", scenario.Name, err)
+			return
+		}
+
+		genesis.SetHeights(&config)
+
+		projected := projection.Run(config)
+		last := projected.Rows[len(projected.Rows)-1]
+
+		fmt.Printf("%s: day %d inflation %s circulating supply %s total supply %s\n", scenario.Name, last.Day,
+			last.Inflation.String(), last.CirculatingSupply.RoundInt().String(), last.TotalSupply.RoundInt().String())
+
+		names = append(names, scenario.Name)
+		projections = append(projections, projected)
+	}
+
+	if err = comparable(names, projections); err != nil {
+		fmt.Println("Error comparing scenarios:", err)
+		return
+	}
+
+	file, err := os.Create(*csvStr)
+	if err != nil {
+		fmt.Println("Error creating csv file")
+		return
+	}
+	defer file.Close()
+
+	err = WriteComparisonCSV(csv.NewWriter(file), names, projections)
+	if err != nil {
+		fmt.Println("Error writing to csv:", err)
+		return
+	}
+
+	fmt.Printf("\nDone\n")
+}
+
+// firstScenario loads the first scenario of the file, or the defaults when there is no file
+func firstScenario(path string) (scenarioModule.Scenario, error) {
+	if path == "" {
+		return scenarioModule.Scenario{}, nil
+	}
+
+	scenarios, err := scenarioModule.Load(path)
+	if err != nil {
+		return scenarioModule.Scenario{}, err
+	}
+
+	return scenarios[0], nil
+}
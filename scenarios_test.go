@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	mintingTypes "github.com/cosmos/cosmos-sdk/x/mint/types"
+	"github.com/stretchr/testify/assert"
+
+	mintModule "github.com/brianosaurus/challenge2/mint"
+	"github.com/brianosaurus/challenge2/projection"
+)
+
+func TestWriteComparisonCSV(t *testing.T) {
+	vestingOnDays := map[int]sdk.Dec{1: sdk.NewDec(1000)}
+
+	project := func(anchor int64, days int, granularity string) projection.Projection {
+		return projection.Run(projection.Config{
+			VestingOnDays: &vestingOnDays,
+			TotalSupply:   sdk.NewDec(1000000000),
+			StakedTokens:  sdk.NewDec(500000000),
+			Model:         mintModule.NewStandardModel(mintingTypes.DefaultParams(), mintingTypes.DefaultInitialMinter()),
+			Fees:          projection.NoFees(),
+			Anchor:        anchor,
+			Days:          days,
+			Granularity:   granularity,
+		})
+	}
+
+	// 2022-11-22
+	base := project(1669075200, 3, "")
+	shorter := project(1669075200, 2, "")
+
+	var buf bytes.Buffer
+	assert.Nil(t, WriteComparisonCSV(csv.NewWriter(&buf), []string{"base", "shorter"}, []projection.Projection{base, shorter}))
+
+	lines, err := csv.NewReader(strings.NewReader(buf.String())).ReadAll()
+	assert.Nil(t, err)
+	assert.Equal(t, 5, len(lines))
+	assert.Equal(t, "base Inflation", lines[0][2])
+	assert.Equal(t, "shorter Inflation", lines[0][6])
+	assert.Equal(t, []string{"start", "2022-11-22"}, lines[1][:2])
+	assert.Equal(t, []string{"0", "2022-11-22"}, lines[2][:2])
+	assert.Equal(t, []string{"1", "2022-11-23"}, lines[3][:2])
+	assert.Equal(t, lines[3][2:6], lines[3][6:10])
+
+	// the shorter scenario has no last day
+	assert.Equal(t, []string{"2", "2022-11-24"}, lines[4][:2])
+	assert.Equal(t, []string{"", "", "", ""}, lines[4][6:])
+
+	// rows of another anchor or granularity are for other periods
+	buf.Reset()
+	err = WriteComparisonCSV(csv.NewWriter(&buf), []string{"base", "later"}, []projection.Projection{base, project(1669161600, 3, "")})
+	assert.EqualError(t, err, "scenario later is anchored at 2022-11-23T00:00:00Z and base at 2022-11-22T00:00:00Z")
+	assert.Empty(t, buf.String())
+
+	err = WriteComparisonCSV(csv.NewWriter(&buf), []string{"base", "weekly"}, []projection.Projection{base, project(1669075200, 14, "week")})
+	assert.EqualError(t, err, "scenario weekly has granularity week and base has day")
+}
@@ -0,0 +1,82 @@
+-- The schema of the sqlite database written by `genesisAnalyzer export -format sqlite`.
+--
+-- Token amounts are INTEGER columns in the smallest denom (e.g. uumee). sqlite integers are 64 bit, amounts
+-- that do not fit (chains with 18 decimals) are stored as REAL and lose precision. Rates and ratios are REAL.
+-- Dates are YYYY-MM-DD in UTC and times are unix timestamps.
+
+-- what the database was made from: chain_id, genesis_hash, genesis_time, anchor_time, granularity and inflation_model
+CREATE TABLE metadata (
+    key   TEXT PRIMARY KEY,
+    value TEXT NOT NULL
+);
+
+-- every account in the auth module, type is the @type of the account. A draft genesis can list an address
+-- twice so addresses are not unique here or in balances and validators.
+CREATE TABLE accounts (
+    address        TEXT NOT NULL,
+    type           TEXT NOT NULL,
+    account_number INTEGER
+);
+
+CREATE INDEX accounts_address ON accounts (address);
+
+-- every coin held by an address in the bank module
+CREATE TABLE balances (
+    address TEXT NOT NULL,
+    denom   TEXT NOT NULL,
+    amount  INTEGER NOT NULL
+);
+
+CREATE INDEX balances_address ON balances (address);
+
+-- the continuous and delayed vesting accounts. start_time is only set for continuous accounts.
+CREATE TABLE vesting_accounts (
+    address          TEXT PRIMARY KEY,
+    type             TEXT NOT NULL, -- continuous or delayed
+    denom            TEXT NOT NULL,
+    original_vesting INTEGER NOT NULL,
+    start_time       INTEGER,
+    start_date       TEXT,
+    end_time         INTEGER NOT NULL,
+    end_date         TEXT NOT NULL
+);
+
+-- the tokens each vesting account unvests on each day of the projection, days are counted from the anchor time
+CREATE TABLE vesting_schedule (
+    address TEXT NOT NULL REFERENCES vesting_accounts (address),
+    day     INTEGER NOT NULL,
+    date    TEXT NOT NULL,
+    amount  INTEGER NOT NULL,
+    PRIMARY KEY (address, day)
+);
+
+-- the validators created by gen_txs or in the staking state of an exported genesis
+CREATE TABLE validators (
+    operator_address  TEXT NOT NULL,
+    moniker           TEXT NOT NULL,
+    delegator_address TEXT,
+    tokens            INTEGER NOT NULL,
+    commission_rate   REAL NOT NULL,
+    max_rate          REAL NOT NULL,
+    max_change_rate   REAL NOT NULL,
+    jailed            INTEGER NOT NULL -- 0 or 1
+);
+
+-- the projection, one row per period like the csv. period is only set when the granularity is not day.
+-- staking_rewards is the total since the anchor, the other amounts are for the period of the row.
+CREATE TABLE projection (
+    row                INTEGER PRIMARY KEY,
+    day                INTEGER NOT NULL,
+    date               TEXT NOT NULL,
+    timestamp          INTEGER NOT NULL,
+    height             INTEGER,
+    period             TEXT,
+    tokens_unvesting   INTEGER NOT NULL,
+    inflation          REAL NOT NULL,
+    staking_rewards    INTEGER NOT NULL,
+    circulating_supply INTEGER NOT NULL,
+    total_supply       INTEGER NOT NULL,
+    fees_collected     INTEGER NOT NULL,
+    tokens_burned      INTEGER NOT NULL,
+    net_supply_change  INTEGER NOT NULL
+);
@@ -0,0 +1,164 @@
+package sqlite
+
+import (
+	"database/sql"
+	_ "embed"
+	"errors"
+	"os"
+	"sort"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	_ "modernc.org/sqlite" // pure go driver, no cgo needed
+
+	"github.com/brianosaurus/challenge2/projection"
+	stakingModule "github.com/brianosaurus/challenge2/staking"
+	vestingModule "github.com/brianosaurus/challenge2/vesting"
+)
+
+// SCHEMA creates the tables, see schema.sql for what is in them
+//
+//go:embed schema.sql
+var SCHEMA string
+
+// Data is everything that goes into the database
+type Data struct {
+	Metadata        map[string]string
+	Accounts        []vestingModule.AuthAccount
+	Balances        []vestingModule.Balance
+	VestingAccounts []vestingModule.Account
+	Validators      []stakingModule.Validator
+	// the vesting schedule is counted in days from the anchor of the projection
+	Projection projection.Projection
+}
+
+// Write creates the database at path with the data, replacing any file already there
+func Write(path string, data Data) error {
+	err := os.Remove(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	_, err = db.Exec(SCHEMA)
+	if err != nil {
+		return err
+	}
+
+	// one transaction for everything, sqlite is slow when every insert commits on its own
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+
+	err = insert(tx, data)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func insert(tx *sql.Tx, data Data) error {
+	keys := []string{}
+	for key := range data.Metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if _, err := tx.Exec(`INSERT INTO metadata VALUES (?, ?)`, key, data.Metadata[key]); err != nil {
+			return err
+		}
+	}
+
+	for _, account := range data.Accounts {
+		_, err := tx.Exec(`INSERT INTO accounts VALUES (?, ?, ?)`, account.Address, account.Type, nullable(account.AccountNumber))
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, balance := range data.Balances {
+		_, err := tx.Exec(`INSERT INTO balances VALUES (?, ?, ?)`, balance.Address, balance.Denom, balance.Amount.String())
+		if err != nil {
+			return err
+		}
+	}
+
+	anchor := data.Projection.Anchor
+	for _, account := range data.VestingAccounts {
+		var startTime, startDate interface{}
+		if account.Type == vestingModule.ACCOUNT_CONTINUOUS {
+			startTime, startDate = account.StartTime, date(account.StartTime)
+		}
+
+		_, err := tx.Exec(`INSERT INTO vesting_accounts VALUES (?, ?, ?, ?, ?, ?, ?, ?)`, account.Address, account.Type, account.Denom,
+			account.OriginalVesting.String(), startTime, startDate, account.EndTime, date(account.EndTime))
+		if err != nil {
+			return err
+		}
+
+		for day, amount := range account.ScheduleAt(anchor) {
+			_, err = tx.Exec(`INSERT INTO vesting_schedule VALUES (?, ?, ?, ?)`, account.Address, day, date(anchor+int64(day)*86400),
+				amount.RoundInt().String())
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, validator := range data.Validators {
+		_, err := tx.Exec(`INSERT INTO validators VALUES (?, ?, ?, ?, ?, ?, ?, ?)`, validator.OperatorAddress, validator.Moniker,
+			nullable(validator.DelegatorAddress), validator.Tokens.RoundInt().String(), float(validator.CommissionRate),
+			float(validator.MaxRate), float(validator.MaxChangeRate), validator.Jailed)
+		if err != nil {
+			return err
+		}
+	}
+
+	for i, row := range data.Projection.Rows {
+		var height interface{}
+		if row.Height != 0 {
+			height = row.Height
+		}
+
+		_, err := tx.Exec(`INSERT INTO projection VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`, i, row.Day,
+			data.Projection.Date(row).Format("2006-01-02"), row.Time, height, nullable(row.Label()), row.TokensUnvesting.RoundInt().String(),
+			float(row.Inflation), row.StakingRewards.RoundInt().String(), row.CirculatingSupply.RoundInt().String(),
+			row.TotalSupply.RoundInt().String(), row.FeesCollected.RoundInt().String(), row.TokensBurned.RoundInt().String(),
+			row.NetSupplyChange.RoundInt().String())
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func date(unix int64) string {
+	return time.Unix(unix, 0).UTC().Format("2006-01-02")
+}
+
+// nullable stores empty strings as NULL
+func nullable(str string) interface{} {
+	if str == "" {
+		return nil
+	}
+
+	return str
+}
+
+func float(value sdk.Dec) float64 {
+	if value.IsNil() {
+		return 0
+	}
+
+	return value.MustFloat64()
+}
@@ -0,0 +1,95 @@
+package sqlite
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	mintingTypes "github.com/cosmos/cosmos-sdk/x/mint/types"
+	"github.com/stretchr/testify/assert"
+
+	mintModule "github.com/brianosaurus/challenge2/mint"
+	"github.com/brianosaurus/challenge2/projection"
+	stakingModule "github.com/brianosaurus/challenge2/staking"
+	vestingModule "github.com/brianosaurus/challenge2/vesting"
+)
+
+func TestWrite(t *testing.T) {
+	anchor := int64(1669075200) // 2022-11-22
+
+	vestingAccounts := []vestingModule.Account{
+		{Address: "umee1delayed", Type: vestingModule.ACCOUNT_DELAYED, Denom: "uumee", OriginalVesting: sdk.NewInt(5000), EndTime: anchor + 2*86400},
+	}
+
+	vestingOnDays := vestingAccounts[0].ScheduleAt(anchor)
+	projected := projection.Run(projection.Config{
+		VestingOnDays: &vestingOnDays,
+		TotalSupply:   sdk.NewDec(1000000000),
+		StakedTokens:  sdk.NewDec(500000000),
+		Model:         mintModule.NewStandardModel(mintingTypes.DefaultParams(), mintingTypes.DefaultInitialMinter()),
+		Fees:          projection.NoFees(),
+		Anchor:        anchor,
+	})
+
+	data := Data{
+		Metadata: map[string]string{"chain_id": "umee-1"},
+		Accounts: []vestingModule.AuthAccount{
+			{Address: "umee1delayed", Type: "/cosmos.vesting.v1beta1.DelayedVestingAccount", AccountNumber: "7"},
+			{Address: "umee1base", Type: "/cosmos.auth.v1beta1.BaseAccount"},
+		},
+		Balances: []vestingModule.Balance{
+			{Address: "umee1delayed", Denom: "uumee", Amount: sdk.NewInt(5000)},
+			// bigger than an int64
+			{Address: "umee1base", Denom: "aevmos", Amount: sdk.NewDec(10).Power(20).TruncateInt()},
+		},
+		VestingAccounts: vestingAccounts,
+		Validators: []stakingModule.Validator{
+			{Moniker: "cheap", OperatorAddress: "umeevaloper1cheap", Tokens: sdk.NewDec(100), CommissionRate: sdk.NewDecWithPrec(5, 2),
+				MaxRate: sdk.NewDecWithPrec(2, 1), MaxChangeRate: sdk.NewDecWithPrec(1, 2)},
+			{Moniker: "greedy", OperatorAddress: "umeevaloper1greedy", Tokens: sdk.NewDec(200), CommissionRate: sdk.NewDecWithPrec(25, 2),
+				MaxRate: sdk.NewDecWithPrec(5, 1), MaxChangeRate: sdk.NewDecWithPrec(1, 2), Jailed: true},
+		},
+		Projection: projected,
+	}
+
+	path := filepath.Join(t.TempDir(), "analysis.db")
+
+	// an old file is replaced
+	assert.Nil(t, os.WriteFile(path, []byte("not a database"), 0644))
+	assert.Nil(t, Write(path, data))
+
+	db, err := sql.Open("sqlite", path)
+	assert.Nil(t, err)
+	defer db.Close()
+
+	var count int
+	var str string
+
+	assert.Nil(t, db.QueryRow(`SELECT value FROM metadata WHERE key = 'chain_id'`).Scan(&str))
+	assert.Equal(t, "umee-1", str)
+
+	assert.Nil(t, db.QueryRow(`SELECT count(*) FROM accounts WHERE account_number IS NULL`).Scan(&count))
+	assert.Equal(t, 1, count)
+
+	assert.Nil(t, db.QueryRow(`SELECT typeof(amount) FROM balances WHERE denom = 'aevmos'`).Scan(&str))
+	assert.Equal(t, "real", str)
+
+	assert.Nil(t, db.QueryRow(`SELECT end_date FROM vesting_accounts WHERE start_time IS NULL`).Scan(&str))
+	assert.Equal(t, "2022-11-24", str)
+
+	var amount int64
+	assert.Nil(t, db.QueryRow(`SELECT date, amount FROM vesting_schedule WHERE address = 'umee1delayed'`).Scan(&str, &amount))
+	assert.Equal(t, "2022-11-24", str)
+	assert.Equal(t, int64(5000), amount)
+
+	assert.Nil(t, db.QueryRow(`SELECT moniker FROM validators WHERE commission_rate > 0.1 AND jailed`).Scan(&str))
+	assert.Equal(t, "greedy", str)
+
+	assert.Nil(t, db.QueryRow(`SELECT count(*) FROM projection WHERE period IS NULL`).Scan(&count))
+	assert.Equal(t, len(projected.Rows), count)
+
+	assert.Nil(t, db.QueryRow(`SELECT tokens_unvesting FROM projection WHERE date = '2022-11-24' AND day = 2`).Scan(&amount))
+	assert.Equal(t, int64(5000), amount)
+}
@@ -2,11 +2,14 @@ package staking
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
+const BOND_STATUS_BONDED = "BOND_STATUS_BONDED"
+
 func GetStakedTokens(appState map[string]interface{}) sdk.Dec {
 	genutil := (appState["genutil"]).(map[string]interface{})
 	genTxs := (genutil["gen_txs"]).([]interface{})
@@ -31,3 +34,157 @@ func GetStakedTokens(appState map[string]interface{}) sdk.Dec {
 
 	return stakedTokens
 }
+
+// GetDelegations returns the tokens each delegator stakes with its gen_tx
+func GetDelegations(appState map[string]interface{}) map[string]sdk.Int {
+	genutil := (appState["genutil"]).(map[string]interface{})
+	genTxs := (genutil["gen_txs"]).([]interface{})
+	delegations := make(map[string]sdk.Int)
+
+	for _, genTx := range genTxs {
+		body := (genTx.(map[string]interface{})["body"]).(map[string]interface{})
+		messages := (body["messages"]).([]interface{})
+
+		for _, message := range messages {
+			fields := message.(map[string]interface{})
+			if fields["@type"] != "/cosmos.staking.v1beta1.MsgCreateValidator" {
+				continue
+			}
+
+			delegator, _ := fields["delegator_address"].(string)
+			amount, ok := sdk.NewIntFromString(fields["value"].(map[string]interface{})["amount"].(string))
+			if !ok {
+				panic(fmt.Sprintln("Error parsing amount", fields["value"]))
+			}
+
+			if previous, ok := delegations[delegator]; ok {
+				amount = amount.Add(previous)
+			}
+
+			delegations[delegator] = amount
+		}
+	}
+
+	return delegations
+}
+
+// Validator is a validator from genesis, either created by a gen_tx or already in the staking state
+// of an exported genesis. Tokens is the self delegation for gen_txs.
+type Validator struct {
+	Moniker          string
+	OperatorAddress  string
+	DelegatorAddress string
+	Tokens           sdk.Dec
+	CommissionRate   sdk.Dec
+	MaxRate          sdk.Dec
+	MaxChangeRate    sdk.Dec
+	Jailed           bool
+	// only validators from the staking state have a status
+	Status string
+}
+
+// GetValidators returns the validators of the genesis sorted by tokens, largest first
+func GetValidators(appState map[string]interface{}) []Validator {
+	validators := []Validator{}
+
+	if genutil, ok := appState["genutil"].(map[string]interface{}); ok {
+		genTxs, _ := genutil["gen_txs"].([]interface{})
+
+		for _, genTx := range genTxs {
+			body := (genTx.(map[string]interface{})["body"]).(map[string]interface{})
+			messages := (body["messages"]).([]interface{})
+
+			for _, message := range messages {
+				msg := message.(map[string]interface{})
+				if msg["@type"] != "/cosmos.staking.v1beta1.MsgCreateValidator" {
+					continue
+				}
+
+				description, _ := msg["description"].(map[string]interface{})
+				commission, _ := msg["commission"].(map[string]interface{})
+				value, _ := msg["value"].(map[string]interface{})
+
+				validators = append(validators, Validator{
+					Moniker:          stringOf(description, "moniker"),
+					OperatorAddress:  stringOf(msg, "validator_address"),
+					DelegatorAddress: stringOf(msg, "delegator_address"),
+					Tokens:           decOf(value, "amount"),
+					CommissionRate:   decOf(commission, "rate"),
+					MaxRate:          decOf(commission, "max_rate"),
+					MaxChangeRate:    decOf(commission, "max_change_rate"),
+				})
+			}
+		}
+	}
+
+	// exported genesis files have the validators in the staking state instead of gen_txs
+	if staking, ok := appState["staking"].(map[string]interface{}); ok {
+		stakingValidators, _ := staking["validators"].([]interface{})
+
+		for _, validator := range stakingValidators {
+			v := validator.(map[string]interface{})
+			description, _ := v["description"].(map[string]interface{})
+			commission, _ := v["commission"].(map[string]interface{})
+			rates, _ := commission["commission_rates"].(map[string]interface{})
+			jailed, _ := v["jailed"].(bool)
+
+			validators = append(validators, Validator{
+				Moniker:         stringOf(description, "moniker"),
+				OperatorAddress: stringOf(v, "operator_address"),
+				Tokens:          decOf(v, "tokens"),
+				CommissionRate:  decOf(rates, "rate"),
+				MaxRate:         decOf(rates, "max_rate"),
+				MaxChangeRate:   decOf(rates, "max_change_rate"),
+				Jailed:          jailed,
+				Status:          stringOf(v, "status"),
+			})
+		}
+	}
+
+	sort.SliceStable(validators, func(i, j int) bool {
+		return validators[i].Tokens.GT(validators[j].Tokens)
+	})
+
+	return validators
+}
+
+// GetBondedTokens returns the tokens of the bonded validators in the staking state of an exported genesis,
+// false when there is no staking state
+func GetBondedTokens(appState map[string]interface{}) (sdk.Dec, bool) {
+	staking, ok := appState["staking"].(map[string]interface{})
+	if !ok {
+		return sdk.Dec{}, false
+	}
+
+	if _, ok = staking["validators"].([]interface{}); !ok {
+		return sdk.Dec{}, false
+	}
+
+	bonded := sdk.ZeroDec()
+	for _, validator := range GetValidators(appState) {
+		if validator.Status == BOND_STATUS_BONDED {
+			bonded = bonded.Add(validator.Tokens)
+		}
+	}
+
+	return bonded, true
+}
+
+func stringOf(object map[string]interface{}, key string) string {
+	str, _ := object[key].(string)
+	return str
+}
+
+func decOf(object map[string]interface{}, key string) sdk.Dec {
+	str, ok := object[key].(string)
+	if !ok {
+		return sdk.ZeroDec()
+	}
+
+	dec, err := sdk.NewDecFromStr(str)
+	if err != nil {
+		panic(fmt.Sprintln("Error parsing", key, err))
+	}
+
+	return dec
+}
@@ -103,4 +103,49 @@ func TestGetVestingAccounts(t *testing.T) {
 	stakedTokens := GetStakedTokens(appState)
 
 	assert.Equal(t, sdk.NewDec(1000000), stakedTokens)
-}
\ No newline at end of file
+
+	delegations := GetDelegations(appState)
+	assert.Equal(t, map[string]sdk.Int{"umee1n3mhyp9fvcmuu8l0q8qvjy07x0rql8q4dtsqwh": sdk.NewInt(1000000)}, delegations)
+}
+func TestGetValidators(t *testing.T) {
+	// create a decoder
+	stringReader := strings.NewReader(STAKING_ACCOUNTS)
+	decoder := json.NewDecoder(stringReader)
+
+	var appState = make(map[string]interface{})
+
+	// decode the json file into the map
+	err := decoder.Decode(&appState)
+	if err != nil {
+		t.Log("Error decoding json")
+		t.FailNow()
+	}
+
+	// an exported genesis keeps its validators in the staking state
+	appState["staking"] = map[string]interface{}{
+		"validators": []interface{}{
+			map[string]interface{}{
+				"operator_address": "umeevaloper1qqq",
+				"jailed":           true,
+				"tokens":           "5000000",
+				"description":      map[string]interface{}{"moniker": "bigger"},
+				"commission": map[string]interface{}{
+					"commission_rates": map[string]interface{}{"rate": "0.050000000000000000", "max_rate": "0.200000000000000000", "max_change_rate": "0.010000000000000000"},
+				},
+			},
+		},
+	}
+
+	validators := GetValidators(appState)
+
+	assert.Equal(t, 2, len(validators))
+	assert.Equal(t, "bigger", validators[0].Moniker)
+	assert.Equal(t, sdk.NewDec(5000000), validators[0].Tokens)
+	assert.True(t, validators[0].Jailed)
+	assert.Equal(t, "0base.vc", validators[1].Moniker)
+	assert.Equal(t, "umeevaloper1n3mhyp9fvcmuu8l0q8qvjy07x0rql8q4d0h0la", validators[1].OperatorAddress)
+	assert.Equal(t, "umee1n3mhyp9fvcmuu8l0q8qvjy07x0rql8q4dtsqwh", validators[1].DelegatorAddress)
+	assert.Equal(t, sdk.NewDec(1000000), validators[1].Tokens)
+	assert.Equal(t, sdk.NewDecWithPrec(2, 2), validators[1].CommissionRate)
+	assert.Equal(t, sdk.NewDecWithPrec(1, 1), validators[1].MaxRate)
+}
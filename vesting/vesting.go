@@ -3,11 +3,13 @@ package vesting
 import (
 	"encoding/json"
 	"fmt"
-	"math/big"
+	"sort"
 	"strconv"
 	"time"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	authTypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	vestingExported "github.com/cosmos/cosmos-sdk/x/auth/vesting/exported"
 	vestingTypes "github.com/cosmos/cosmos-sdk/x/auth/vesting/types"
 
 	"github.com/cosmos/cosmos-sdk/codec"
@@ -95,6 +97,14 @@ func GetVestingAccounts(appState map[string]interface{}) (map[string]*vestingTyp
 
 func GetTotalSupplyAndVestingSchedule(appState map[string]interface{}, continuousAccounts map[string]*vestingTypes.ContinuousVestingAccount,
 	delayedAccounts map[string]*vestingTypes.DelayedVestingAccount,
+) (sdk.Dec, *map[int]sdk.Dec) {
+	return GetTotalSupplyAndVestingScheduleAt(appState, continuousAccounts, delayedAccounts, TheTime)
+}
+
+// GetTotalSupplyAndVestingScheduleAt is GetTotalSupplyAndVestingSchedule with the days counted from
+// anchor (a unix timestamp) instead of TheTime
+func GetTotalSupplyAndVestingScheduleAt(appState map[string]interface{}, continuousAccounts map[string]*vestingTypes.ContinuousVestingAccount,
+	delayedAccounts map[string]*vestingTypes.DelayedVestingAccount, anchor int64,
 ) (sdk.Dec, *map[int]sdk.Dec) {
 	bank := (appState["bank"]).(map[string]interface{})
 	balances := (bank["balances"]).([]interface{})
@@ -125,55 +135,397 @@ func GetTotalSupplyAndVestingSchedule(appState map[string]interface{}, continuou
 
 	vestingOnDays := make(map[int]sdk.Dec)
 
-	for _, account := range continuousAccounts {
-		amount := account.OriginalVesting[0].Amount // there are only 1 coin in the array for all accounts in genesis.json
-		startTime := account.StartTime
-		endTime := account.EndTime
+	for _, account := range GetAccounts(continuousAccounts, delayedAccounts) {
+		totalSupply = totalSupply.Add(sdk.NewDecFromInt(account.OriginalVesting))
+
+		for day, amount := range account.ScheduleAt(anchor) {
+			if _, ok := vestingOnDays[day]; !ok {
+				vestingOnDays[day] = sdk.NewDec(0)
+			}
+
+			vestingOnDays[day] = vestingOnDays[day].Add(amount)
+		}
+	}
+
+	return totalSupply, &vestingOnDays 
+}
+
+const (
+	ACCOUNT_CONTINUOUS = "continuous"
+	ACCOUNT_DELAYED    = "delayed"
+)
+
+// Account is a vesting account from genesis. StartTime is only set for continuous accounts.
+// Delegated is its delegated_vesting plus delegated_free, what it had staked when genesis was made.
+type Account struct {
+	Address         string
+	Type            string
+	Denom           string
+	OriginalVesting sdk.Int
+	StartTime       int64
+	EndTime         int64
+	Delegated       sdk.Int
+}
 
-		totalSupply = totalSupply.Add(sdk.NewDecFromBigInt(amount.BigInt()))
+// GetAccounts returns the continuous and delayed vesting accounts together, sorted by address
+func GetAccounts(continuousAccounts map[string]*vestingTypes.ContinuousVestingAccount,
+	delayedAccounts map[string]*vestingTypes.DelayedVestingAccount,
+) []Account {
+	accounts := []Account{}
+
+	// there are only 1 coin in the original vesting for all accounts in genesis.json
+	for address, account := range continuousAccounts {
+		denom := account.OriginalVesting[0].Denom
+		accounts = append(accounts, Account{Address: address, Type: ACCOUNT_CONTINUOUS, Denom: denom,
+			OriginalVesting: account.OriginalVesting[0].Amount, StartTime: account.StartTime, EndTime: account.EndTime,
+			Delegated: account.DelegatedVesting.AmountOf(denom).Add(account.DelegatedFree.AmountOf(denom))})
+	}
+
+	for address, account := range delayedAccounts {
+		denom := account.OriginalVesting[0].Denom
+		accounts = append(accounts, Account{Address: address, Type: ACCOUNT_DELAYED, Denom: denom,
+			OriginalVesting: account.OriginalVesting[0].Amount, EndTime: account.EndTime,
+			Delegated: account.DelegatedVesting.AmountOf(denom).Add(account.DelegatedFree.AmountOf(denom))})
+	}
+
+	sort.Slice(accounts, func(i, j int) bool {
+		return accounts[i].Address < accounts[j].Address
+	})
+
+	return accounts
+}
+
+// ScheduleAt returns the tokens of the account that unvest on each day counted from anchor (a unix timestamp).
+// Day N runs from anchor + N days up to the second before the next day. The amounts come from the
+// GetVestingCoins of the SDK account at the start and end of each day, so accounts that start later are
+// included and the days add up to exactly what is still vesting at anchor.
+func (a Account) ScheduleAt(anchor int64) map[int]sdk.Dec {
+	return a.schedule(anchor, anchor)
+}
+
+// ScheduleByDateAt is ScheduleAt with days that run from UTC midnight to midnight instead of from the anchor.
+// Day 0 is the UTC date of anchor and only counts what unvests from anchor on.
+func (a Account) ScheduleByDateAt(anchor int64) map[int]sdk.Dec {
+	return a.schedule(anchor, anchor-anchor%86400)
+}
+
+// schedule splits what unvests from anchor on into days. Day N starts at dayStart + N days, except day 0 which
+// starts at anchor.
+func (a Account) schedule(anchor int64, dayStart int64) map[int]sdk.Dec {
+	schedule := make(map[int]sdk.Dec)
+
+	if a.EndTime < anchor {
+		return schedule
+	}
+
+	account := a.sdkAccount()
+
+	// the second before a day starts is the last second of the day before it
+	vesting := func(day int) sdk.Int {
+		start := anchor
+		if day > 0 {
+			start = dayStart + int64(day)*86400
+		}
+
+		coins := account.GetVestingCoins(time.Unix(start-1, 0))
+		if coins.Empty() {
+			return sdk.ZeroInt()
+		}
+
+		return coins[0].Amount
+	}
+
+	firstDay := 0
+	if a.StartTime > anchor {
+		firstDay = int((a.StartTime - dayStart) / 86400)
+	}
 
-		if startTime > TheTime {
+	before := vesting(firstDay)
+	for day := firstDay; day <= int((a.EndTime-dayStart)/86400); day++ {
+		after := vesting(day + 1)
+
+		if unvested := before.Sub(after); unvested.IsPositive() {
+			schedule[day] = sdk.NewDecFromInt(unvested)
+		}
+
+		before = after
+	}
+
+	return schedule
+}
+
+// GetDelegated is what the vesting accounts had staked when genesis was made
+func GetDelegated(accounts []Account) sdk.Dec {
+	delegated := sdk.ZeroDec()
+
+	for _, account := range accounts {
+		if !account.Delegated.IsNil() {
+			delegated = delegated.Add(sdk.NewDecFromInt(account.Delegated))
+		}
+	}
+
+	return delegated
+}
+
+// GetDelegatedVestingAt returns the staked tokens of the vesting accounts that have not vested at anchor and how
+// much of them vests on each day counted from anchor. Like the SDK the delegation of an account counts as vesting
+// until less than it is left to vest. delegations are the gen_tx delegations by address, they are not in
+// delegated_vesting and delegated_free yet.
+func GetDelegatedVestingAt(accounts []Account, delegations map[string]sdk.Int, anchor int64) (sdk.Dec, *map[int]sdk.Dec) {
+	delegatedVesting := sdk.ZeroDec()
+	delegatedVestingOnDays := make(map[int]sdk.Dec)
+
+	for _, account := range accounts {
+		delegated := sdk.ZeroDec()
+		if !account.Delegated.IsNil() {
+			delegated = sdk.NewDecFromInt(account.Delegated)
+		}
+
+		if delegation, ok := delegations[account.Address]; ok {
+			delegated = delegated.Add(sdk.NewDecFromInt(delegation))
+		}
+
+		if !delegated.IsPositive() {
 			continue
 		}
 
-		// math to get the number of tokens that have vested in a continuous vesting account
-		secondsTokenHasBeenVesting := big.NewInt(0).Sub(big.NewInt(endTime), big.NewInt(startTime))
-		numberOfFiveSecondChunksTokenHasBeenVesting := big.NewInt(0).Div(secondsTokenHasBeenVesting, big.NewInt(SECONDS_PER_BLOCK))
-		numberOfTokensVestingInTotalDuringTimeQuanta := big.NewInt(amount.Int64())
-		tokensVestedPerBlock := amount.BigInt().Div(numberOfTokensVestingInTotalDuringTimeQuanta, numberOfFiveSecondChunksTokenHasBeenVesting)
-		tokensVestedPerDay := tokensVestedPerBlock.Mul(tokensVestedPerBlock, big.NewInt((60 / SECONDS_PER_BLOCK) * 60 * 24)) 
+		schedule := account.ScheduleAt(anchor)
+
+		days := []int{}
+		vesting := sdk.ZeroDec()
+		for day, amount := range schedule {
+			days = append(days, day)
+			vesting = vesting.Add(amount)
+		}
+		sort.Ints(days)
+
+		before := sdk.MinDec(delegated, vesting)
+		delegatedVesting = delegatedVesting.Add(before)
+
+		for _, day := range days {
+			vesting = vesting.Sub(schedule[day])
 
-		// add the tokens that have not vested to the map by days since today
-		daysLeft := int((endTime - TheTime) / 86400)
+			after := sdk.MinDec(delegated, vesting)
+			if before.GT(after) {
+				if _, ok := delegatedVestingOnDays[day]; !ok {
+					delegatedVestingOnDays[day] = sdk.ZeroDec()
+				}
 
-		for vestingDay := 0; vestingDay < daysLeft; vestingDay++ {
-			if _, ok := vestingOnDays[vestingDay]; !ok {
-				vestingOnDays[vestingDay] = sdk.NewDec(0)
+				delegatedVestingOnDays[day] = delegatedVestingOnDays[day].Add(before.Sub(after))
 			}
 
-			vestingOnDays[vestingDay] = vestingOnDays[vestingDay].Add(sdk.NewDecFromBigInt(tokensVestedPerDay))
+			before = after
+		}
+	}
+
+	return delegatedVesting, &delegatedVestingOnDays
+}
+
+// sdkAccount is the account as the SDK sees it, so the vesting math is the same as on chain
+func (a Account) sdkAccount() vestingExported.VestingAccount {
+	// there is only 1 coin in the original vesting for all accounts in genesis.json
+	originalVesting := sdk.Coins{sdk.Coin{Denom: a.Denom, Amount: a.OriginalVesting}}
+	baseVestingAccount := vestingTypes.NewBaseVestingAccount(&authTypes.BaseAccount{Address: a.Address}, originalVesting, a.EndTime)
+
+	if a.Type == ACCOUNT_DELAYED {
+		return vestingTypes.NewDelayedVestingAccountRaw(baseVestingAccount)
+	}
+
+	return vestingTypes.NewContinuousVestingAccountRaw(baseVestingAccount, a.StartTime)
+}
+
+// Status is where a vesting account stands at a time. Schedule is the tokens left to unvest by day
+// counted from that time, NextUnlock is 0 once everything has vested.
+type Status struct {
+	Account
+	Vested           sdk.Dec
+	Unvested         sdk.Dec
+	NextUnlock       int64
+	NextUnlockAmount sdk.Dec
+	Schedule         map[int]sdk.Dec
+}
+
+// StatusAt returns how much of the account has vested at (a unix timestamp), how much has not and when it unlocks next
+func (a Account) StatusAt(at int64) Status {
+	status := Status{Account: a, Unvested: sdk.ZeroDec(), NextUnlockAmount: sdk.ZeroDec(), Schedule: a.ScheduleAt(at)}
+
+	next := -1
+	for day, amount := range status.Schedule {
+		status.Unvested = status.Unvested.Add(amount)
+
+		if next == -1 || day < next {
+			next = day
 		}
 	}
 
-	for _, account := range delayedAccounts {
-		amount := account.OriginalVesting[0].Amount // there are only 1 coin in the array for all accounts in genesis.json
-		endTime := account.EndTime
+	status.Vested = sdk.NewDecFromInt(a.OriginalVesting).Sub(status.Unvested)
 
-		totalSupply = totalSupply.Add(sdk.NewDecFromBigInt(amount.BigInt()))
+	if next != -1 {
+		status.NextUnlock = at + int64(next)*86400
+		status.NextUnlockAmount = status.Schedule[next]
 
-		if endTime < TheTime {
+		// delayed accounts unlock at their end time and continuous ones from their start time, not at the start of the day
+		if a.Type == ACCOUNT_DELAYED {
+			status.NextUnlock = a.EndTime
+		} else if a.StartTime > status.NextUnlock {
+			status.NextUnlock = a.StartTime
+		}
+	}
+
+	return status
+}
+
+// AuthAccount is any account in the auth module of genesis, Type is its @type
+type AuthAccount struct {
+	Address       string
+	Type          string
+	AccountNumber string
+}
+
+// GetAuthAccounts returns every account in the auth module in the order of genesis
+func GetAuthAccounts(appState map[string]interface{}) []AuthAccount {
+	auth := (appState["auth"]).(map[string]interface{})
+	accounts := (auth["accounts"]).([]interface{})
+
+	authAccounts := []AuthAccount{}
+
+	for _, account := range accounts {
+		fields := account.(map[string]interface{})
+		accountType, _ := fields["@type"].(string)
+
+		// the base account is nested deeper the more specialized the account is
+		for _, key := range []string{"base_vesting_account", "base_account"} {
+			if nested, ok := fields[key].(map[string]interface{}); ok {
+				fields = nested
+			}
+		}
+
+		address, _ := fields["address"].(string)
+		accountNumber, _ := fields["account_number"].(string)
+
+		authAccounts = append(authAccounts, AuthAccount{Address: address, Type: accountType, AccountNumber: accountNumber})
+	}
+
+	return authAccounts
+}
+
+// Balance is an amount of one denom held by an address in the bank module
+type Balance struct {
+	Address string
+	Denom   string
+	Amount  sdk.Int
+}
+
+// GetBalances returns every coin of every balance in the bank module in the order of genesis
+func GetBalances(appState map[string]interface{}) []Balance {
+	bank := (appState["bank"]).(map[string]interface{})
+	balances := (bank["balances"]).([]interface{})
+
+	coins := []Balance{}
+
+	for _, account := range balances {
+		baseAccount := account.(map[string]interface{})
+		address := baseAccount["address"].(string)
+
+		for _, coin := range baseAccount["coins"].([]interface{}) {
+			denom := coin.(map[string]interface{})["denom"].(string)
+			amount, ok := sdk.NewIntFromString(coin.(map[string]interface{})["amount"].(string))
+			if !ok {
+				panic(fmt.Sprintln("Error parsing amount of", address))
+			}
+
+			coins = append(coins, Balance{Address: address, Denom: denom, Amount: amount})
+		}
+	}
+
+	return coins
+}
+
+// GetSupply returns the supply of denom in the bank module. Exported genesis files record it, otherwise it is
+// the sum of the balances.
+func GetSupply(appState map[string]interface{}, denom string) sdk.Int {
+	bank := (appState["bank"]).(map[string]interface{})
+
+	if supply, ok := bank["supply"].([]interface{}); ok {
+		for _, coin := range supply {
+			fields := coin.(map[string]interface{})
+			if fields["denom"] != denom {
+				continue
+			}
+
+			amount, ok := sdk.NewIntFromString(fields["amount"].(string))
+			if !ok {
+				panic(fmt.Sprintln("Error parsing supply of", denom))
+			}
+
+			return amount
+		}
+	}
+
+	total := sdk.ZeroInt()
+	for _, balance := range GetBalances(appState) {
+		if balance.Denom == denom {
+			total = total.Add(balance.Amount)
+		}
+	}
+
+	return total
+}
+
+// Holder is an address from genesis with the amount of tokens it holds. Vesting accounts hold their
+// original vesting, the same amount that is counted in the total supply.
+type Holder struct {
+	Address string
+	Amount  sdk.Dec
+	Vesting bool
+}
+
+// GetHolders returns every address holding tokens in genesis, largest first
+func GetHolders(appState map[string]interface{}, continuousAccounts map[string]*vestingTypes.ContinuousVestingAccount,
+	delayedAccounts map[string]*vestingTypes.DelayedVestingAccount,
+) []Holder {
+	bank := (appState["bank"]).(map[string]interface{})
+	balances := (bank["balances"]).([]interface{})
+
+	holders := []Holder{}
+
+	for _, account := range balances {
+		baseAccount := account.(map[string]interface{})
+		address := baseAccount["address"].(string)
+
+		// vesting accounts are added below from their original vesting
+		if _, ok := continuousAccounts[address]; ok {
 			continue
 		}
 
-		// add the tokens that have not vested to the map by the Nth day since today
-		vestingDay := int((endTime - TheTime) / 86400)
+		if _, ok := delayedAccounts[address]; ok {
+			continue
+		}
 
-		if _, ok := vestingOnDays[vestingDay]; !ok {
-			vestingOnDays[vestingDay] = sdk.NewDec(0)
+		amount, ok := sdk.NewIntFromString(baseAccount["coins"].([]interface{})[0].(map[string]interface{})["amount"].(string))
+		if !ok {
+			panic(fmt.Sprintln("Error parsing amount of", address))
 		}
 
-		vestingOnDays[vestingDay] = vestingOnDays[vestingDay].Add(sdk.NewDecFromBigInt(amount.BigInt()))
+		holders = append(holders, Holder{Address: address, Amount: sdk.NewDecFromInt(amount)})
 	}
 
-	return totalSupply, &vestingOnDays 
-}
\ No newline at end of file
+	for address, account := range continuousAccounts {
+		holders = append(holders, Holder{Address: address, Amount: sdk.NewDecFromInt(account.OriginalVesting[0].Amount), Vesting: true})
+	}
+
+	for address, account := range delayedAccounts {
+		holders = append(holders, Holder{Address: address, Amount: sdk.NewDecFromInt(account.OriginalVesting[0].Amount), Vesting: true})
+	}
+
+	// the accounts come out of maps so sort by address too to keep the order the same every time
+	sort.Slice(holders, func(i, j int) bool {
+		if !holders[i].Amount.Equal(holders[j].Amount) {
+			return holders[i].Amount.GT(holders[j].Amount)
+		}
+
+		return holders[i].Address < holders[j].Address
+	})
+
+	return holders
+}
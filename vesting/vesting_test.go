@@ -6,7 +6,9 @@ import (
 	big "math/big"
 
 	"testing"
+	"time"
 
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -174,25 +176,206 @@ func TestGetTotalSupplyAndVestingSchedule(t *testing.T) {
 
 	// day zero on the vesting schedule is really day 1 of vesting. How computers count which is to say
 	// indexes start at zero.
-	vestingStart, success := totalSupply.BigInt().SetString("12295065600000000000000000000", 10)
+	vestingStart, success := totalSupply.BigInt().SetString("12295081968000000000000000000", 10)
 	if !success {
 		t.Log("Error converting string to big int")
 		t.FailNow()
 	}
 
-	vestingEnd, success := totalSupply.BigInt().SetString("12295065600000000000000000000", 10)
+	vestingEnd, success := totalSupply.BigInt().SetString("12295081967000000000000000000", 10)
 	if !success {
 		t.Log("Error converting string to big int")
 		t.FailNow()
 	}
 
 	assert.Equal(t, bigTotalSupply, totalSupply.BigInt())
-	assert.Equal(t, 818, len(*vestingOnDays))
+	assert.Equal(t, 819, len(*vestingOnDays))
 	assert.Equal(t, vestingStart, ((*vestingOnDays)[0].BigInt()))
 	assert.Equal(t, vestingEnd, ((*vestingOnDays)[817].BigInt()))
 
-	// make sure it is vesting for the proper amount of days
+	// make sure it is vesting for the proper amount of days, the last one is cut short by the end time
 	address := "umee1wqr08242ysrepqgzm6q0mn7ndcnjlsf6vdxd0v"
-	assert.Equal(t, int64(len(*vestingOnDays)), (continuousVestingAccounts[address].EndTime - TheTime) / 86400)
+	assert.Equal(t, int64(len(*vestingOnDays)), (continuousVestingAccounts[address].EndTime - TheTime) / 86400 + 1)
+
+	// what is left to vest adds up to exactly what the SDK says is still vesting
+	unvesting := sdk.ZeroDec()
+	for _, amount := range *vestingOnDays {
+		unvesting = unvesting.Add(amount)
+	}
+	stillVesting := continuousVestingAccounts[address].GetVestingCoins(time.Unix(TheTime-1, 0)).AmountOf("uumee")
+	stillVesting = stillVesting.Add(delayedVestingAccounts["umee1agzky2ak6xs5vve3c2wzjtqdq7fwadcgj2mxf9"].OriginalVesting.AmountOf("uumee"))
+	assert.Equal(t, sdk.NewDecFromInt(stillVesting), unvesting)
+}
+
+
+func TestGetHolders(t *testing.T) {
+	// create a decoder
+	stringReader := strings.NewReader(AUTH_VESTING_ACCOUNTS)
+	decoder := json.NewDecoder(stringReader)
+
+	var appState = make(map[string]interface{})
+
+	// decode the json file into the map
+	err := decoder.Decode(&appState)
+	if err != nil {
+		t.Log("Error decoding json")
+		t.FailNow()
+	}
+
+	continuousVestingAccounts, delayedVestingAccounts := GetVestingAccounts(appState)
+
+	stringReader = strings.NewReader(BANK_BALANCES)
+	decoder = json.NewDecoder(stringReader)
+
+	appState = make(map[string]interface{})
+
+	// decode the json file into the map
+	err = decoder.Decode(&appState)
+	if err != nil {
+		t.Log("Error decoding json")
+		t.FailNow()
+	}
+
+	holders := GetHolders(appState, continuousVestingAccounts, delayedVestingAccounts)
+
+	assert.Equal(t, 5, len(holders))
+	assert.Equal(t, "umee1wqr08242ysrepqgzm6q0mn7ndcnjlsf6vdxd0v", holders[0].Address)
+	assert.True(t, holders[0].Vesting)
+	assert.Equal(t, "umee1agzky2ak6xs5vve3c2wzjtqdq7fwadcgj2mxf9", holders[1].Address)
+	assert.Equal(t, "umee1qqq8fdsrcsz4jnlvrcqa6ds2ruflgrgeguyeh0", holders[2].Address)
+	assert.False(t, holders[2].Vesting)
+	assert.Equal(t, "8333000000", holders[2].Amount.RoundInt().String())
+	assert.Equal(t, "7143000000", holders[4].Amount.RoundInt().String())
 }
 
+func TestGetAccounts(t *testing.T) {
+	var appState = make(map[string]interface{})
+
+	err := json.Unmarshal([]byte(AUTH_VESTING_ACCOUNTS), &appState)
+	if err != nil {
+		t.Log("Error decoding json")
+		t.FailNow()
+	}
+
+	authAccounts := GetAuthAccounts(appState)
+	assert.Equal(t, 2, len(authAccounts))
+	assert.Equal(t, "umee1agzky2ak6xs5vve3c2wzjtqdq7fwadcgj2mxf9", authAccounts[0].Address)
+	assert.Equal(t, "/cosmos.vesting.v1beta1.DelayedVestingAccount", authAccounts[0].Type)
+	assert.Equal(t, "0", authAccounts[0].AccountNumber)
+
+	continuousVestingAccounts, delayedVestingAccounts := GetVestingAccounts(appState)
+	accounts := GetAccounts(continuousVestingAccounts, delayedVestingAccounts)
+
+	assert.Equal(t, 2, len(accounts))
+	assert.Equal(t, ACCOUNT_DELAYED, accounts[0].Type)
+	assert.Equal(t, ACCOUNT_CONTINUOUS, accounts[1].Type)
+	assert.Equal(t, int64(1660582800), accounts[1].StartTime)
+	assert.Equal(t, "uumee", accounts[1].Denom)
+
+	// the delayed account unlocks all at once, the continuous one a little every day
+	delayed := accounts[0].ScheduleAt(1668956141)
+	assert.Equal(t, 1, len(delayed))
+	assert.Equal(t, sdk.NewDec(309282000000), delayed[87])
+
+	continuous := accounts[1].ScheduleAt(1668956141)
+	assert.Equal(t, 819, len(continuous))
+	assert.Equal(t, sdk.NewDec(12295081968), continuous[0])
+
+	// an account that has not started yet unvests all of its tokens from its start
+	future := accounts[1].ScheduleAt(accounts[1].StartTime - 10*86400)
+	_, ok := future[9]
+	assert.False(t, ok)
+	total := sdk.ZeroDec()
+	for _, amount := range future {
+		total = total.Add(amount)
+	}
+	assert.Equal(t, sdk.NewDecFromInt(accounts[1].OriginalVesting), total)
+
+	// nothing is left to unvest after the end
+	assert.Equal(t, 0, len(accounts[0].ScheduleAt(1700000000)))
+
+	// by date the anchor at 15:35 UTC only leaves part of the first date, the total is the same
+	byDate := accounts[1].ScheduleByDateAt(1668956141)
+	assert.True(t, byDate[0].LT(continuous[0]))
+
+	total, byDateTotal := sdk.ZeroDec(), sdk.ZeroDec()
+	for day := range continuous {
+		total = total.Add(continuous[day])
+	}
+	for day := range byDate {
+		byDateTotal = byDateTotal.Add(byDate[day])
+	}
+	assert.Equal(t, total, byDateTotal)
+
+	status := accounts[0].StatusAt(1668956141)
+	assert.True(t, status.Vested.IsZero())
+	assert.Equal(t, sdk.NewDec(309282000000), status.Unvested)
+	assert.Equal(t, accounts[0].EndTime, status.NextUnlock)
+	assert.Equal(t, sdk.NewDec(309282000000), status.NextUnlockAmount)
+
+	status = accounts[1].StatusAt(1668956141)
+	assert.Equal(t, int64(1668956141), status.NextUnlock)
+	assert.Equal(t, sdk.NewDec(12295081968), status.NextUnlockAmount)
+	assert.Equal(t, sdk.NewDecFromInt(accounts[1].OriginalVesting), status.Vested.Add(status.Unvested))
+
+	// everything has vested after the end
+	status = accounts[0].StatusAt(1700000000)
+	assert.Equal(t, sdk.NewDecFromInt(accounts[0].OriginalVesting), status.Vested)
+	assert.Equal(t, int64(0), status.NextUnlock)
+
+	// nothing has vested before the start
+	status = accounts[1].StatusAt(1660000000)
+	assert.True(t, status.Vested.IsZero())
+	assert.Equal(t, accounts[1].StartTime, status.NextUnlock)
+
+	err = json.Unmarshal([]byte(BANK_BALANCES), &appState)
+	if err != nil {
+		t.Log("Error decoding json")
+		t.FailNow()
+	}
+
+	balances := GetBalances(appState)
+	assert.Equal(t, 3, len(balances))
+	assert.Equal(t, "umee1qqqk0gxu4he52m0t2w6f6vfag6uvyaegprmj58", balances[1].Address)
+	assert.Equal(t, "uumee", balances[1].Denom)
+	assert.Equal(t, sdk.NewInt(7500000000), balances[1].Amount)
+}
+
+func TestGetDelegatedVestingAt(t *testing.T) {
+	var appState = make(map[string]interface{})
+
+	// the delayed account of AUTH_VESTING_ACCOUNTS with a delegation
+	delegatedJson := strings.Replace(AUTH_VESTING_ACCOUNTS, `"delegated_free": [],
+ 					"delegated_vesting": [],
+ 					"end_time": "1676480400"`, `"delegated_free": [{"denom": "uumee", "amount": "1000"}],
+ 					"delegated_vesting": [{"denom": "uumee", "amount": "2000"}],
+ 					"end_time": "1676480400"`, 1)
+
+	err := json.Unmarshal([]byte(delegatedJson), &appState)
+	if err != nil {
+		t.Log("Error decoding json")
+		t.FailNow()
+	}
+
+	continuousVestingAccounts, delayedVestingAccounts := GetVestingAccounts(appState)
+	accounts := GetAccounts(continuousVestingAccounts, delayedVestingAccounts)
+	assert.Equal(t, sdk.NewInt(3000), accounts[0].Delegated)
+	assert.Equal(t, sdk.NewDec(3000), GetDelegated(accounts))
+
+	// the continuous account stakes with its gen_tx, its delegation vests once less than it is left to vest
+	delegations := map[string]sdk.Int{"umee1wqr08242ysrepqgzm6q0mn7ndcnjlsf6vdxd0v": sdk.NewInt(20000000000)}
+	delegatedVesting, delegatedVestingOnDays := GetDelegatedVestingAt(accounts, delegations, 1668956141)
+	assert.Equal(t, sdk.NewDec(20000003000), delegatedVesting)
+	assert.Equal(t, sdk.NewDec(3000), (*delegatedVestingOnDays)[87])
+
+	_, ok := (*delegatedVestingOnDays)[815]
+	assert.False(t, ok)
+	assert.Equal(t, sdk.NewDec(6643328780), (*delegatedVestingOnDays)[816])
+	assert.Equal(t, sdk.NewDec(12295081967), (*delegatedVestingOnDays)[817])
+
+	total := sdk.ZeroDec()
+	for _, amount := range *delegatedVestingOnDays {
+		total = total.Add(amount)
+	}
+	assert.Equal(t, delegatedVesting, total)
+}